@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	API_HOST         = "127.0.0.1"
+	API_DEFAULT_PORT = 17830
+	TriggerAPI       = "api"
+)
+
+//go:embed api/openapi.json
+var openAPIDocument []byte
+
+// APISettings configures the loopback REST API
+type APISettings struct {
+	Enabled bool   `json:"enabled"`
+	Port    int    `json:"port"`
+	Token   string `json:"token"` // sent as "Authorization: Bearer <token>", "X-API-Token" or ?token=
+}
+
+// apiServer runs the REST API while it is enabled
+type apiServer struct {
+	mu      sync.Mutex
+	server  *http.Server
+	streams map[*websocket.Conn]struct{} // open event stream connections
+}
+
+// apiError is the JSON body of failed requests
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// generateAPIToken returns a random token for the REST API
+func generateAPIToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate API token: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// start listens on the loopback interface and serves handler in the background
+func (s *apiServer) start(port int, handler http.Handler) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.server != nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(API_HOST, fmt.Sprint(port)))
+	if err != nil {
+		return fmt.Errorf("failed to start API server: %v", err)
+	}
+
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	s.server = server
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("API server failed", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// stop shuts the server down, waiting briefly for running requests
+func (s *apiServer) stop() {
+	s.mu.Lock()
+	server := s.server
+	s.server = nil
+	s.mu.Unlock()
+
+	if server == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server.Shutdown(ctx)
+	s.closeStreams()
+}
+
+// startAPI starts the REST API when it is enabled in the settings
+func (a *App) startAPI() error {
+	settings := a.currentSettings().API
+	if !settings.Enabled {
+		return nil
+	}
+	return a.api.start(settings.Port, a.apiHandler())
+}
+
+// apiHandler returns the routes of the REST API
+func (a *App) apiHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPIDocument)
+	})
+
+	mux.Handle("GET /api/profiles", a.apiAuth(a.apiGetProfiles))
+	mux.Handle("POST /api/profiles", a.apiAuth(a.apiSaveProfile))
+	mux.Handle("DELETE /api/profiles/{name}", a.apiAuth(a.apiDeleteProfile))
+	mux.Handle("POST /api/profiles/{name}/apply", a.apiAuth(a.apiApplyProfile))
+	mux.Handle("GET /api/monitors", a.apiAuth(a.apiGetMonitors))
+	mux.Handle("GET /api/audio-devices", a.apiAuth(a.apiGetAudioDevices))
+	mux.HandleFunc("GET /api/events", a.serveEventStream)
+
+	return mux
+}
+
+// apiAuthorized reports whether the request carries the configured token
+func (a *App) apiAuthorized(r *http.Request) bool {
+	token := r.Header.Get("X-API-Token")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = bearer
+	}
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+
+	expected := a.currentSettings().API.Token
+	return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// apiAuth rejects requests without the configured token
+func (a *App) apiAuth(handler func(r *http.Request) (int, interface{})) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.apiAuthorized(r) {
+			writeAPIResponse(w, http.StatusUnauthorized, apiError{Error: "invalid or missing API token"})
+			return
+		}
+
+		status, body := handler(r)
+		writeAPIResponse(w, status, body)
+	})
+}
+
+// writeAPIResponse writes body as JSON, errors are wrapped in apiError
+func writeAPIResponse(w http.ResponseWriter, status int, body interface{}) {
+	if err, ok := body.(error); ok {
+		body = apiError{Error: err.Error()}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (a *App) apiGetProfiles(r *http.Request) (int, interface{}) {
+	return http.StatusOK, a.currentProfiles()
+}
+
+func (a *App) apiSaveProfile(r *http.Request) (int, interface{}) {
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err)
+	}
+	if err := validateProfileName(body.Name); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	if err := a.saveCurrentProfile(body.Name); err != nil {
+		if errors.Is(err, errProfileExists) {
+			return http.StatusConflict, err
+		}
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusCreated, a.findProfile(body.Name)
+}
+
+func (a *App) apiDeleteProfile(r *http.Request) (int, interface{}) {
+	name := r.PathValue("name")
+	if err := validateProfileName(name); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if a.findProfile(name) == nil {
+		return http.StatusNotFound, fmt.Errorf("profile not found: %s", name)
+	}
+
+	if err := a.DeleteProfile(name); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, cliStatus{OK: true}
+}
+
+func (a *App) apiApplyProfile(r *http.Request) (int, interface{}) {
+	name := r.PathValue("name")
+	if a.findProfile(name) == nil {
+		return http.StatusNotFound, fmt.Errorf("profile not found: %s", name)
+	}
+
+	if err := a.autoApplyProfile(TriggerAPI, name, "REST API request"); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, cliStatus{OK: true}
+}
+
+func (a *App) apiGetMonitors(r *http.Request) (int, interface{}) {
+	monitors, err := a.readMonitors()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, a.filterIgnoredMonitors(monitors)
+}
+
+func (a *App) apiGetAudioDevices(r *http.Request) (int, interface{}) {
+	devices, err := a.readVisibleAudioDevices()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, devices
+}
+
+// GetAPISettings returns the REST API settings
+func (a *App) GetAPISettings() APISettings {
+	return a.currentSettings().API
+}
+
+// SetAPISettings updates the REST API settings and restarts the server. A
+// token is generated when the API is enabled without one.
+func (a *App) SetAPISettings(settings APISettings) error {
+	if settings.Port <= 0 || settings.Port > 65535 {
+		return fmt.Errorf("invalid API port: %d", settings.Port)
+	}
+
+	if settings.Enabled && settings.Token == "" {
+		token, err := generateAPIToken()
+		if err != nil {
+			return err
+		}
+		settings.Token = token
+	}
+
+	a.api.stop()
+	err := a.updateSettings(func(s *Settings) error {
+		s.API = settings
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.startAPI()
+}
+
+// RegenerateAPIToken replaces the REST API token and returns the new one
+func (a *App) RegenerateAPIToken() (string, error) {
+	token, err := generateAPIToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = a.updateSettings(func(s *Settings) error {
+		s.API.Token = token
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
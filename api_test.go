@@ -0,0 +1,72 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const apiTestToken = "test-token"
+
+// apiRequest sends an authorized request to the API handler
+func apiRequest(t *testing.T, app *App, method string, target string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer "+apiTestToken)
+	recorder := httptest.NewRecorder()
+	app.apiHandler().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func newTestAPIApp(t *testing.T) *App {
+	t.Helper()
+
+	app, _ := newTestApp(t)
+	err := app.updateSettings(func(s *Settings) error {
+		s.API.Token = apiTestToken
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestAPISaveProfileConflict(t *testing.T) {
+	app := newTestAPIApp(t)
+
+	if got := apiRequest(t, app, http.MethodPost, "/api/profiles", `{"name": "Desk"}`); got.Code != http.StatusCreated {
+		t.Fatalf("first POST status = %d, body %s", got.Code, got.Body)
+	}
+
+	got := apiRequest(t, app, http.MethodPost, "/api/profiles", `{"name": "Desk"}`)
+	if got.Code != http.StatusConflict {
+		t.Errorf("duplicate POST status = %d, want %d, body %s", got.Code, http.StatusConflict, got.Body)
+	}
+	if profiles := app.currentProfiles(); len(profiles) != 1 {
+		t.Errorf("profiles = %+v, want a single Desk", profiles)
+	}
+}
+
+func TestAPIRejectsInvalidProfileNames(t *testing.T) {
+	app := newTestAPIApp(t)
+
+	for _, body := range []string{`{"name": ""}`, `{"name": "../settings"}`, `{"name": "a\\b"}`, `{"name": "C:desk"}`} {
+		if got := apiRequest(t, app, http.MethodPost, "/api/profiles", body); got.Code != http.StatusBadRequest {
+			t.Errorf("POST %s status = %d, want %d", body, got.Code, http.StatusBadRequest)
+		}
+	}
+
+	// %2E%2E is decoded to ".." in the path value
+	if got := apiRequest(t, app, http.MethodDelete, "/api/profiles/%2E%2E", ""); got.Code != http.StatusBadRequest {
+		t.Errorf("DELETE .. status = %d, want %d", got.Code, http.StatusBadRequest)
+	}
+	if got := apiRequest(t, app, http.MethodDelete, "/api/profiles/..%5Csettings", ""); got.Code != http.StatusBadRequest {
+		t.Errorf(`DELETE ..\settings status = %d, want %d`, got.Code, http.StatusBadRequest)
+	}
+	if got := apiRequest(t, app, http.MethodDelete, "/api/profiles/Missing", ""); got.Code != http.StatusNotFound {
+		t.Errorf("DELETE Missing status = %d, want %d", got.Code, http.StatusNotFound)
+	}
+}
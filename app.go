@@ -3,9 +3,19 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"log/slog"
+	"maps"
 	"monitor-profile-manager-wails/pkg/audio"
+	"monitor-profile-manager-wails/pkg/audit"
+	"monitor-profile-manager-wails/pkg/hooks"
+	"monitor-profile-manager-wails/pkg/hotkeys"
+	"monitor-profile-manager-wails/pkg/instance"
+	"monitor-profile-manager-wails/pkg/integrity"
+	"monitor-profile-manager-wails/pkg/logging"
 	"monitor-profile-manager-wails/pkg/monitors"
+	"monitor-profile-manager-wails/pkg/processes"
+	"monitor-profile-manager-wails/pkg/schedule"
+	"monitor-profile-manager-wails/pkg/webhooks"
 	"os"
 	"path/filepath"
 	"sync"
@@ -24,14 +34,20 @@ var monitorEnumMutex sync.Mutex
 // Global mutex to prevent concurrent audio device enumeration
 var audioEnumMutex sync.Mutex
 
+// Global mutex to prevent concurrent profile application
+var applyMutex sync.Mutex
+
 type Monitor struct {
 	DeviceName  string `json:"deviceName"`
 	DisplayName string `json:"displayName"`
 	IsPrimary   bool   `json:"isPrimary"`
 	IsActive    bool   `json:"isActive"`
+	IsConnected bool   `json:"isConnected"`
 	IsEnabled   bool   `json:"isEnabled"` // user-controlled enable/disable state
 	MonitorId   string `json:"monitorId"`
 	Nickname    string `json:"nickname"` // optional custom nickname
+
+	layout *monitors.Layout // resolution and position when active, not saved
 }
 
 type AudioDevice struct {
@@ -39,14 +55,11 @@ type AudioDevice struct {
 	Name      string `json:"name"`
 	IsDefault bool   `json:"isDefault"`
 	IsEnabled bool   `json:"isEnabled"`
+	State     string `json:"state"`    // svcl device state: Active, Disabled, Unplugged or Not Present
 	Selected  bool   `json:"selected"` // whether this device is selected for the profile
 	Nickname  string `json:"nickname"` // optional custom nickname
 }
 
-type IgnoreList struct {
-	AudioDevices []string `json:"audioDevices"`
-}
-
 type NicknameStorage struct {
 	Monitors     map[string]string `json:"monitors"`     // deviceID -> nickname
 	AudioDevices map[string]string `json:"audioDevices"` // deviceID -> nickname
@@ -54,19 +67,77 @@ type NicknameStorage struct {
 
 // App struct holds the application state
 type App struct {
-	ctx          context.Context
+	ctx context.Context
+
+	// stateMu guards monitors, audioDevices, profiles, settings, nicknames
+	// and ignoreRules, which are read from the watchers, the scheduler and the
+	// API as well as bound methods. Slices and maps are only ever replaced,
+	// never modified in place.
+	stateMu      sync.RWMutex
 	monitors     []Monitor
 	audioDevices []AudioDevice
 	profiles     []Profile
-	ignoreList   IgnoreList
+	settings     Settings
 	nicknames    NicknameStorage
-	audioTools   *audio.AudioTools
-	monitorTools *monitors.MonitorTools
+	ignoreRules  []IgnoreRule
+	settingsMu   sync.Mutex // serializes updateSettings
+	profilesMu   sync.Mutex // serializes updateProfiles and readProfiles
+	nicknamesMu  sync.Mutex // serializes updateNicknames
+	ignoreMu     sync.Mutex // serializes updateIgnoreRules
+
+	undoStack       []SavedState
+	audioTools      *audio.AudioTools
+	monitorTools    *monitors.MonitorTools
+	events          *EventBus
+	watcher         *DeviceWatcher
+	enforcer        *driftEnforcer
+	automation      *automationState
+	history         *applyHistory
+	scheduler       *schedule.Scheduler
+	processWatcher  *ProcessWatcher
+	processTriggers *processTriggerState
+	hotkeys         *hotkeys.Manager
+	launch          LaunchOptions
+	headless        bool // running a CLI command without the window and tray
+	instance        *instance.Server
+	api             *apiServer
+	mqtt            *mqttBridge
+	webhooks        *webhooks.Dispatcher
+	hookRunner      *hooks.Runner
+	logs            *logging.Logger
+	toolAudit       *audit.Trail
+	toolAuditFile   *logging.RotatingFile
+	toolVerifier    *integrity.Verifier
+	toolsTempDir    string // removed on shutdown, empty when the tool cache is used
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	app := &App{}
+	app := &App{
+		settings:        defaultSettings(),
+		events:          NewEventBus(),
+		enforcer:        newDriftEnforcer(),
+		automation:      &automationState{},
+		history:         &applyHistory{},
+		scheduler:       schedule.NewScheduler(schedule.SystemClock{}),
+		processTriggers: &processTriggerState{},
+		api:             &apiServer{},
+		mqtt:            &mqttBridge{},
+		webhooks:        webhooks.NewDispatcher(WEBHOOK_MAX_ATTEMPTS, WEBHOOK_RETRY_BACKOFF, MAX_WEBHOOK_DELIVERIES),
+		hookRunner:      hooks.NewRunner(),
+		toolAudit:       audit.NewTrail(MAX_TOOL_INVOCATIONS, MAX_TOOL_POLLS, nil),
+	}
+	app.toolVerifier = integrity.NewVerifier(app.handleToolIntegrity)
+	// Log to memory until startLogging opens the log file
+	app.logs, _ = logging.New(logging.Options{MaxRecent: LOG_MAX_RECENT})
+	app.watcher = NewDeviceWatcher(DEVICE_WATCH_INTERVAL, app.snapshotDevices, app.emitEvent)
+	app.processWatcher = NewProcessWatcher(processes.NewProcessTools(), PROCESS_WATCH_INTERVAL, app.watchedExecutables, app.handleProcessChanges)
+	app.hotkeys = hotkeys.NewManager(hotkeys.NewPlatform(), app.applyProfileFromHotkey)
+	app.events.Subscribe(app.handleEnforcementEvent)
+	app.events.Subscribe(app.handleTopologyEvent)
+	app.events.Subscribe(app.handleAudioRuleEvent)
+	app.events.Subscribe(app.handleMQTTEvent)
+	app.events.Subscribe(app.handleWebhookEvent)
 	return app
 }
 
@@ -79,138 +150,225 @@ func (a *App) startup(ctx context.Context) {
 
 	a.ctx = ctx
 
-	// Extract embedded tools to temporary directory
-	if toolsDir, err := extractTools(); err != nil {
-		fmt.Printf("Failed to extract tools: %v\n", err)
+	// Extract embedded tools to the tool cache. Without them the tools are
+	// looked up at the configured paths and on PATH.
+	toolsDir, err := a.extractTools()
+	if err != nil {
+		slog.Error("Failed to extract tools", "error", err)
 	} else {
-		a.audioTools = audio.NewAudioTools(toolsDir)
-		a.monitorTools = monitors.NewMonitorTools(toolsDir)
-		fmt.Printf("Tools extracted to: %s\n", toolsDir)
+		slog.Info("Tools extracted", "dir", toolsDir)
 	}
+	a.audioTools = audio.NewAudioTools(toolsDir, a.toolAudit, a.toolVerifier)
+	a.monitorTools = monitors.NewMonitorTools(toolsDir, a.toolAudit, a.toolVerifier)
 
 	// Load all components with error handling to prevent crashes
 	if err := func() error {
+		a.loadSettings()
 		a.loadIgnoreList()
 		a.loadNicknames()
 		a.loadMonitors()
 		a.loadAudioDevices()
 		a.loadProfiles()
+		a.loadUndoStack()
 		return nil
 	}(); err != nil {
 		// If startup fails, initialize with empty defaults
+		a.stateMu.Lock()
 		a.monitors = []Monitor{}
 		a.audioDevices = []AudioDevice{}
 		a.profiles = []Profile{}
-		a.ignoreList = IgnoreList{AudioDevices: []string{}}
-		a.nicknames = NicknameStorage{
-			Monitors:     make(map[string]string),
-			AudioDevices: make(map[string]string),
-		}
+		a.stateMu.Unlock()
+		a.storeIgnoreRules([]IgnoreRule{})
+		a.storeNicknames(newNicknameStorage())
 	}
-}
 
-// loadIgnoreList loads the audio device ignore list from disk
-func (a *App) loadIgnoreList() {
-	ignoreListPath := a.getIgnoreListPath()
-	data, err := os.ReadFile(ignoreListPath)
-	if err != nil {
-		a.ignoreList = IgnoreList{AudioDevices: []string{}}
-		return
+	// Topology and audio rules react to changes from the devices found at startup
+	a.seedTopology(a.currentMonitors())
+	a.seedAudioRuleStates(a.currentAudioDevices())
+
+	// Reflect the persisted automation state in the tray
+	sendAutomationPaused(a.currentSettings().Automation.Paused)
+
+	// Watch for hotplugged monitors and audio devices
+	a.watcher.Start()
+
+	// Apply profiles at their scheduled times
+	a.reloadSchedules()
+	a.scheduler.Start()
+
+	// Apply profiles while configured processes are running
+	a.processWatcher.Start()
+
+	// Apply profiles from their global hotkeys
+	if err := a.hotkeys.Start(); err != nil {
+		slog.Error("Failed to start hotkeys", "error", err)
+	} else if err := a.registerHotkeys(); err != nil {
+		slog.Error("Failed to register hotkeys", "error", err)
 	}
 
-	var ignoreList IgnoreList
-	if err := json.Unmarshal(data, &ignoreList); err != nil {
-		a.ignoreList = IgnoreList{AudioDevices: []string{}}
-		return
+	// Apply the profile requested on the command line or the login profile
+	go a.applyLaunchProfile()
+
+	// Serve the loopback REST API when enabled
+	if err := a.startAPI(); err != nil {
+		slog.Error("Failed to start API", "error", err)
 	}
 
-	a.ignoreList = ignoreList
-}
+	// Publish state to the MQTT broker when enabled
+	if err := a.startMQTT(); err != nil {
+		slog.Error("Failed to start MQTT", "error", err)
+	}
 
-// saveIgnoreList saves the audio device ignore list to disk
-func (a *App) saveIgnoreList() error {
-	ignoreListPath := a.getIgnoreListPath()
-	data, err := json.MarshalIndent(a.ignoreList, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal ignore list: %v", err)
+	// Handle the arguments of later launches
+	if a.instance != nil {
+		go a.instance.Serve()
 	}
+}
 
-	if err := os.WriteFile(ignoreListPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to save ignore list: %v", err)
+// shutdown is called when the app is closing
+func (a *App) shutdown(ctx context.Context) {
+	a.watcher.Stop()
+	a.enforcer.check.stop()
+	a.automation.topology.stop()
+	a.automation.audio.stop()
+	a.scheduler.Stop()
+	a.processWatcher.Stop()
+	a.hotkeys.Stop()
+	a.api.stop()
+	a.stopMQTT()
+	a.webhooks.Stop()
+	if a.instance != nil {
+		a.instance.Close()
 	}
+	a.removeToolsTempDir()
+}
 
-	return nil
+// currentMonitors returns the cached monitors. The slice is shared and must
+// not be modified.
+func (a *App) currentMonitors() []Monitor {
+	a.stateMu.RLock()
+	defer a.stateMu.RUnlock()
+
+	return a.monitors
+}
+
+// storeMonitors replaces the cached monitors
+func (a *App) storeMonitors(monitors []Monitor) {
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+
+	a.monitors = monitors
+}
+
+// currentAudioDevices returns the cached audio devices. The slice is shared
+// and must not be modified.
+func (a *App) currentAudioDevices() []AudioDevice {
+	a.stateMu.RLock()
+	defer a.stateMu.RUnlock()
+
+	return a.audioDevices
 }
 
-// getIgnoreListPath returns the path where the ignore list is stored
-func (a *App) getIgnoreListPath() string {
-	profilesDir := a.getProfilesDir()
-	return filepath.Join(profilesDir, "ignore_list.json")
+// storeAudioDevices replaces the cached audio devices
+func (a *App) storeAudioDevices(devices []AudioDevice) {
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+
+	a.audioDevices = devices
 }
 
 // loadMonitors loads monitors using the OS-specific implementation
 func (a *App) loadMonitors() {
+	appMonitors, err := a.readMonitors()
+	if err == nil && len(appMonitors) > 0 {
+		a.storeMonitors(appMonitors)
+	}
+}
 
+// readMonitors enumerates monitors with MultiMonitorTool and applies nicknames
+func (a *App) readMonitors() ([]Monitor, error) {
+	return a.readMonitorsWith(a.monitorTools)
+}
+
+// readMonitorsWith enumerates monitors with the given tools, which record the
+// invocation with their origin
+func (a *App) readMonitorsWith(tools *monitors.MonitorTools) ([]Monitor, error) {
 	// Prevent concurrent monitor loading
 	monitorEnumMutex.Lock()
 	defer monitorEnumMutex.Unlock()
 
-	monitors, err := a.monitorTools.GetMonitorList()
+	monitors, err := tools.GetMonitorList()
+	if err != nil {
+		return nil, err
+	}
 
 	appMonitors := make([]Monitor, 0)
-	if err == nil {
-		for _, monitor := range monitors {
-			appMonitor := Monitor{}
-			appMonitor.IsActive = monitor.GetActive()
-			appMonitor.IsPrimary = monitor.GetPrimary()
-			appMonitor.DeviceName = monitor.GetName()
-			appMonitor.MonitorId = monitor.GetMonitorID()
-			appMonitor.DisplayName = monitor.GetMonitorName()
-
-			appMonitors = append(appMonitors, appMonitor)
+	for _, monitor := range monitors {
+		appMonitor := Monitor{}
+		appMonitor.IsActive = monitor.GetActive()
+		appMonitor.IsConnected = !monitor.GetDisconnected()
+		appMonitor.IsPrimary = monitor.GetPrimary()
+		appMonitor.DeviceName = monitor.GetName()
+		appMonitor.MonitorId = monitor.GetMonitorID()
+		appMonitor.DisplayName = monitor.GetMonitorName()
+		if layout, ok := monitor.GetLayout(); ok {
+			appMonitor.layout = &layout
 		}
 
+		appMonitors = append(appMonitors, appMonitor)
 	}
 
-	if len(appMonitors) > 0 {
-		// Apply nicknames to monitors
-		for i := range appMonitors {
-			if nickname := a.GetMonitorNickname(appMonitors[i].DeviceName); nickname != "" {
-				appMonitors[i].Nickname = nickname
-			}
-			// Ensure isEnabled is always set
-			if !appMonitors[i].IsEnabled {
-				appMonitors[i].IsEnabled = true
-			}
+	// Apply nicknames to monitors
+	for i := range appMonitors {
+		if nickname := a.GetMonitorNickname(appMonitors[i].DeviceName); nickname != "" {
+			appMonitors[i].Nickname = nickname
+		}
+		// Ensure isEnabled is always set
+		if !appMonitors[i].IsEnabled {
+			appMonitors[i].IsEnabled = true
 		}
-
-		a.monitors = appMonitors
 	}
 
+	return appMonitors, nil
 }
 
 // loadAudioDevices loads audio devices using the OS-specific implementation
 func (a *App) loadAudioDevices() {
+	devices, err := a.readAudioDevices()
+	if err != nil {
+		devices = []AudioDevice{}
+	}
+
+	a.storeAudioDevices(devices)
+}
+
+// readAudioDevices enumerates output audio devices with svcl and applies nicknames
+func (a *App) readAudioDevices() ([]AudioDevice, error) {
+	return a.readAudioDevicesWith(a.audioTools)
+}
+
+// readAudioDevicesWith enumerates output audio devices with the given tools,
+// which record the invocation with their origin
+func (a *App) readAudioDevicesWith(tools *audio.AudioTools) ([]AudioDevice, error) {
 	// Prevent concurrent audio device loading
 	audioEnumMutex.Lock()
 	defer audioEnumMutex.Unlock()
 
-	devices := make([]AudioDevice, 0)
-
-	svclDevices, err := a.audioTools.GetActiveOutputDevices()
-
+	svclDevices, err := tools.GetOutputDevices()
 	if err != nil {
-		devices = []AudioDevice{}
-	} else {
-		for _, device := range svclDevices {
-			ad := AudioDevice{}
-
-			ad.IsDefault = device.IsPrimary()
-			ad.IsEnabled = device.IsActive()
-			ad.Name = device.GetName()
-			ad.ID = device.GetCommandLineID()
-			devices = append(devices, ad)
-		}
+		return nil, err
+	}
+
+	devices := make([]AudioDevice, 0)
+	for _, device := range svclDevices {
+		ad := AudioDevice{}
+
+		ad.IsDefault = device.IsPrimary()
+		ad.IsEnabled = device.IsEnabled()
+		ad.State = device.GetDeviceState()
+		ad.Name = device.GetName()
+		ad.ID = device.GetCommandLineID()
+		devices = append(devices, ad)
 	}
 
 	// Apply nicknames to audio devices
@@ -220,19 +378,49 @@ func (a *App) loadAudioDevices() {
 		}
 	}
 
-	a.audioDevices = devices
+	return devices, nil
+}
+
+// readVisibleAudioDevices enumerates the audio devices, excluding ignored devices
+func (a *App) readVisibleAudioDevices() ([]AudioDevice, error) {
+	devices, err := a.readAudioDevices()
+	if err != nil {
+		return nil, err
+	}
+	return a.filterIgnoredAudioDevices(devices), nil
 }
 
-// GetMonitors returns the current list of monitors
+// GetMonitors returns the current list of monitors, excluding ignored monitors
 func (a *App) GetMonitors() []Monitor {
 	a.loadMonitors()
-	return a.monitors
+	return a.filterIgnoredMonitors(a.currentMonitors())
+}
+
+// GetMonitorsWithIgnoreStatus returns monitors with ignore status
+func (a *App) GetMonitorsWithIgnoreStatus() map[string]interface{} {
+	a.loadMonitors()
+
+	filteredMonitors := make([]Monitor, 0)
+	ignoredMonitors := make([]Monitor, 0)
+
+	for _, monitor := range a.currentMonitors() {
+		if a.isMonitorIgnored(monitor) {
+			ignoredMonitors = append(ignoredMonitors, monitor)
+		} else {
+			filteredMonitors = append(filteredMonitors, monitor)
+		}
+	}
+
+	return map[string]interface{}{
+		"filtered": filteredMonitors,
+		"ignored":  ignoredMonitors,
+	}
 }
 
-// GetAudioDevices returns the current list of audio devices
+// GetAudioDevices returns the current list of audio devices, excluding ignored devices
 func (a *App) GetAudioDevices() []AudioDevice {
 	a.loadAudioDevices()
-	return a.audioDevices
+	return a.filterIgnoredAudioDevices(a.currentAudioDevices())
 }
 
 // GetAudioDevicesWithIgnoreStatus returns audio devices with ignore status
@@ -242,8 +430,8 @@ func (a *App) GetAudioDevicesWithIgnoreStatus() map[string]interface{} {
 	filteredDevices := make([]AudioDevice, 0)
 	ignoredDevices := make([]AudioDevice, 0)
 
-	for _, device := range a.audioDevices {
-		isIgnored := a.isDeviceIgnored(device.ID)
+	for _, device := range a.currentAudioDevices() {
+		isIgnored := a.isAudioDeviceIgnored(device)
 		if isIgnored {
 			ignoredDevices = append(ignoredDevices, device)
 		} else {
@@ -257,20 +445,10 @@ func (a *App) GetAudioDevicesWithIgnoreStatus() map[string]interface{} {
 	}
 }
 
-// isDeviceIgnored checks if a device is in the ignore list
-func (a *App) isDeviceIgnored(deviceID string) bool {
-	for _, ignoredID := range a.ignoreList.AudioDevices {
-		if ignoredID == deviceID {
-			return true
-		}
-	}
-	return false
-}
-
-// RefreshMonitors refreshes the monitor list
+// RefreshMonitors refreshes the monitor list, excluding ignored monitors
 func (a *App) RefreshMonitors() []Monitor {
 	a.loadMonitors()
-	return a.monitors
+	return a.filterIgnoredMonitors(a.currentMonitors())
 }
 
 // RefreshAudioDevices refreshes the audio device list
@@ -279,74 +457,93 @@ func (a *App) RefreshAudioDevices() map[string]interface{} {
 	return a.GetAudioDevicesWithIgnoreStatus()
 }
 
-// IgnoreAudioDevice adds a device to the ignore list
-func (a *App) IgnoreAudioDevice(deviceID string) error {
-	// Check if already ignored
-	if a.isDeviceIgnored(deviceID) {
-		return fmt.Errorf("device is already ignored")
-	}
+// Nickname management methods
 
-	a.ignoreList.AudioDevices = append(a.ignoreList.AudioDevices, deviceID)
-	return a.saveIgnoreList()
+// newNicknameStorage returns empty nickname storage
+func newNicknameStorage() NicknameStorage {
+	return NicknameStorage{
+		Monitors:     make(map[string]string),
+		AudioDevices: make(map[string]string),
+	}
 }
 
-// UnignoreAudioDevice removes a device from the ignore list
-func (a *App) UnignoreAudioDevice(deviceID string) error {
-	for i, ignoredID := range a.ignoreList.AudioDevices {
-		if ignoredID == deviceID {
-			a.ignoreList.AudioDevices = append(a.ignoreList.AudioDevices[:i], a.ignoreList.AudioDevices[i+1:]...)
-			return a.saveIgnoreList()
-		}
+// clone returns a copy of the storage that shares no maps with it
+func (n NicknameStorage) clone() NicknameStorage {
+	n.Monitors = maps.Clone(n.Monitors)
+	n.AudioDevices = maps.Clone(n.AudioDevices)
+	if n.Monitors == nil {
+		n.Monitors = make(map[string]string)
+	}
+	if n.AudioDevices == nil {
+		n.AudioDevices = make(map[string]string)
 	}
-	return fmt.Errorf("device is not in ignore list")
+	return n
 }
 
-// Nickname management methods
+// currentNicknames returns the nicknames. The maps in the result are shared
+// and must not be modified, change nicknames with updateNicknames.
+func (a *App) currentNicknames() NicknameStorage {
+	a.stateMu.RLock()
+	defer a.stateMu.RUnlock()
+
+	return a.nicknames
+}
+
+// storeNicknames replaces the nicknames
+func (a *App) storeNicknames(nicknames NicknameStorage) {
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+
+	a.nicknames = nicknames
+}
+
+// updateNicknames applies update to a copy of the nicknames, then stores and
+// saves the copy
+func (a *App) updateNicknames(update func(nicknames *NicknameStorage)) error {
+	a.nicknamesMu.Lock()
+	defer a.nicknamesMu.Unlock()
+
+	nicknames := a.currentNicknames().clone()
+	update(&nicknames)
+	a.storeNicknames(nicknames)
+
+	return a.saveNicknames(nicknames)
+}
 
 // SetMonitorNickname sets a custom nickname for a monitor
 func (a *App) SetMonitorNickname(deviceName string, nickname string) error {
-	if a.nicknames.Monitors == nil {
-		a.nicknames.Monitors = make(map[string]string)
-	}
-	if nickname == "" {
-		delete(a.nicknames.Monitors, deviceName)
-	} else {
-		a.nicknames.Monitors[deviceName] = nickname
-	}
-	return a.saveNicknames()
+	return a.updateNicknames(func(n *NicknameStorage) {
+		if nickname == "" {
+			delete(n.Monitors, deviceName)
+		} else {
+			n.Monitors[deviceName] = nickname
+		}
+	})
 }
 
 // SetAudioDeviceNickname sets a custom nickname for an audio device
 func (a *App) SetAudioDeviceNickname(deviceID string, nickname string) error {
-	if a.nicknames.AudioDevices == nil {
-		a.nicknames.AudioDevices = make(map[string]string)
-	}
-	if nickname == "" {
-		delete(a.nicknames.AudioDevices, deviceID)
-	} else {
-		a.nicknames.AudioDevices[deviceID] = nickname
-	}
-	return a.saveNicknames()
+	return a.updateNicknames(func(n *NicknameStorage) {
+		if nickname == "" {
+			delete(n.AudioDevices, deviceID)
+		} else {
+			n.AudioDevices[deviceID] = nickname
+		}
+	})
 }
 
 // GetMonitorNickname gets the custom nickname for a monitor
 func (a *App) GetMonitorNickname(deviceName string) string {
-	if a.nicknames.Monitors == nil {
-		return ""
-	}
-	return a.nicknames.Monitors[deviceName]
+	return a.currentNicknames().Monitors[deviceName]
 }
 
 // GetAudioDeviceNickname gets the custom nickname for an audio device
 func (a *App) GetAudioDeviceNickname(deviceID string) string {
-	if a.nicknames.AudioDevices == nil {
-		return ""
-	}
-	return a.nicknames.AudioDevices[deviceID]
+	return a.currentNicknames().AudioDevices[deviceID]
 }
 
 // saveNicknames saves the nickname storage to disk
-func (a *App) saveNicknames() error {
+func (a *App) saveNicknames(nicknames NicknameStorage) error {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
 		return err
@@ -359,7 +556,7 @@ func (a *App) saveNicknames() error {
 	}
 
 	nicknamesFile := filepath.Join(appDir, "nicknames.json")
-	data, err := json.MarshalIndent(a.nicknames, "", "  ")
+	data, err := json.MarshalIndent(nicknames, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -379,14 +576,16 @@ func (a *App) loadNicknames() error {
 	if err != nil {
 		if os.IsNotExist(err) {
 			// No nicknames file exists, initialize empty storage
-			a.nicknames = NicknameStorage{
-				Monitors:     make(map[string]string),
-				AudioDevices: make(map[string]string),
-			}
+			a.storeNicknames(newNicknameStorage())
 			return nil
 		}
 		return err
 	}
 
-	return json.Unmarshal(data, &a.nicknames)
+	var nicknames NicknameStorage
+	if err := json.Unmarshal(data, &nicknames); err != nil {
+		return err
+	}
+	a.storeNicknames(nicknames.clone())
+	return nil
 }
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_HOOK_TIMEOUT = 30 * time.Second
+	HookPhasePre         = "pre"
+	HookPhasePost        = "post"
+)
+
+// Hook failure policies
+const (
+	HookFailureContinue = "continue" // log the failure and carry on
+	HookFailureAbort    = "abort"    // fail the apply, a failing pre-apply hook stops it before anything changes
+)
+
+// ApplyHook is a command run before or after a profile is applied. It runs
+// through the system shell with WPM_PROFILE, WPM_PREVIOUS_PROFILE and
+// WPM_HOOK_PHASE set.
+type ApplyHook struct {
+	Command        string `json:"command"`
+	TimeoutSeconds int    `json:"timeoutSeconds"` // 0 uses the default of 30 seconds
+	FailurePolicy  string `json:"failurePolicy"`  // "continue" or "abort"
+}
+
+// HookFailure is the payload of the hook-failed event
+type HookFailure struct {
+	Profile string `json:"profile"`
+	Phase   string `json:"phase"`
+	Command string `json:"command"`
+	Error   string `json:"error"`
+	Output  string `json:"output,omitempty"`
+}
+
+// validate normalizes the policy and checks the hook settings
+func (h *ApplyHook) validate() error {
+	h.Command = strings.TrimSpace(h.Command)
+	if h.Command == "" {
+		return fmt.Errorf("hook command cannot be empty")
+	}
+	if h.TimeoutSeconds < 0 {
+		return fmt.Errorf("hook timeout cannot be negative")
+	}
+
+	switch h.FailurePolicy {
+	case "":
+		h.FailurePolicy = HookFailureContinue
+	case HookFailureContinue, HookFailureAbort:
+	default:
+		return fmt.Errorf("invalid hook failure policy: %s", h.FailurePolicy)
+	}
+
+	return nil
+}
+
+// timeout returns the configured timeout or the default
+func (h ApplyHook) timeout() time.Duration {
+	if h.TimeoutSeconds == 0 {
+		return DEFAULT_HOOK_TIMEOUT
+	}
+	return time.Duration(h.TimeoutSeconds) * time.Second
+}
+
+// runApplyHooks runs the hooks of one phase in order. The first failing hook
+// with the abort policy stops the remaining hooks and its error is returned.
+func (a *App) runApplyHooks(phase string, hooks []ApplyHook, profileName string, previousProfile string) error {
+	env := []string{
+		"WPM_PROFILE=" + profileName,
+		"WPM_PREVIOUS_PROFILE=" + previousProfile,
+		"WPM_HOOK_PHASE=" + phase,
+	}
+
+	for _, hook := range hooks {
+		output, err := a.hookRunner.Run(hook.Command, env, hook.timeout())
+		if err == nil {
+			continue
+		}
+
+		slog.Warn("Apply hook failed", "phase", phase, "profile", profileName, "command", hook.Command, "policy", hook.FailurePolicy, "error", err, "output", output)
+		a.emitEvent(EventHookFailed, HookFailure{
+			Profile: profileName,
+			Phase:   phase,
+			Command: hook.Command,
+			Error:   err.Error(),
+			Output:  output,
+		})
+
+		if hook.FailurePolicy == HookFailureAbort {
+			return fmt.Errorf("%s-apply hook %q: %v", phase, hook.Command, err)
+		}
+	}
+
+	return nil
+}
+
+// SetProfileHooks replaces the commands run before and after a profile is applied
+func (a *App) SetProfileHooks(profileName string, preApply []ApplyHook, postApply []ApplyHook) error {
+	for i := range preApply {
+		if err := preApply[i].validate(); err != nil {
+			return err
+		}
+	}
+	for i := range postApply {
+		if err := postApply[i].validate(); err != nil {
+			return err
+		}
+	}
+
+	return a.updateProfiles(func(profiles []Profile) ([]Profile, error) {
+		index := profileIndex(profiles, profileName)
+		if index < 0 {
+			return nil, fmt.Errorf("profile not found: %s", profileName)
+		}
+
+		profiles[index].PreApply = preApply
+		profiles[index].PostApply = postApply
+		return profiles, nil
+	})
+}
@@ -1,14 +1,16 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"monitor-profile-manager-wails/pkg/audio"
 )
 
 func (a *App) SetPrimaryOutputDevice(deviceId string) error {
 	var aDevice *AudioDevice
-	for i := range a.audioDevices {
-		if a.audioDevices[i].ID == deviceId {
-			aDevice = &a.audioDevices[i]
+	for _, device := range a.currentAudioDevices() {
+		if device.ID == deviceId {
+			aDevice = &device
 			break
 		}
 	}
@@ -17,5 +19,46 @@ func (a *App) SetPrimaryOutputDevice(deviceId string) error {
 		return fmt.Errorf("audio device not found")
 	}
 
+	if aDevice.State != audio.StateActive {
+		return fmt.Errorf("audio device is not active")
+	}
+
 	return a.audioTools.SetPrimaryDevice(aDevice.ID)
 }
+
+// SetAudioDeviceEnabled enables or disables an audio output device
+func (a *App) SetAudioDeviceEnabled(deviceId string, enabled bool) error {
+	if enabled {
+		return a.audioTools.EnableDevice(deviceId)
+	}
+	return a.audioTools.DisableDevice(deviceId)
+}
+
+// isPresent reports whether the device can be enabled or disabled, devices
+// that are unplugged or not present cannot
+func (d AudioDevice) isPresent() bool {
+	return d.State == audio.StateActive || d.State == audio.StateDisabled
+}
+
+// applyAudioDeviceStates enables or disables the devices whose state differs
+// from states. Unplugged and not present devices are skipped, and a device
+// that fails does not stop the others.
+func (a *App) applyAudioDeviceStates(states map[string]bool) error {
+	devices, err := a.readAudioDevices()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, device := range devices {
+		enabled, ok := states[device.ID]
+		if !ok || enabled == device.IsEnabled || !device.isPresent() {
+			continue
+		}
+
+		if err := a.SetAudioDeviceEnabled(device.ID, enabled); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", device.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
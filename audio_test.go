@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	"monitor-profile-manager-wails/pkg/audio"
+)
+
+func TestApplyProfileChangesOnlyPresentDevicesThatDiffer(t *testing.T) {
+	app, tools := newTestApp(t)
+	tools.setAudio(
+		fakeAudioDevice{id: "Speakers", name: "Speakers", state: audio.StateActive, isDefault: true},
+		fakeAudioDevice{id: "Headphones", name: "Headphones", state: audio.StateDisabled},
+		fakeAudioDevice{id: "Monitor", name: "Monitor", state: audio.StateActive},
+		fakeAudioDevice{id: "TV", name: "TV", state: audio.StateUnplugged},
+	)
+
+	request := SaveProfileRequest{
+		Name:                  "Desk",
+		DefaultOutputDeviceId: "Monitor",
+		DeviceStates: map[string]bool{
+			"Speakers":   false,
+			"Headphones": true,
+			"Monitor":    true,
+			"TV":         false,
+			"Dock":       true,
+		},
+	}
+	if err := app.SaveProfile(request); err != nil {
+		t.Fatal(err)
+	}
+
+	// A device that fails does not stop the others or the apply
+	tools.failOn(audio.SvclExe, "/Disable Speakers")
+	tools.resetCalls()
+
+	if err := app.ApplyProfile("Desk"); err != nil {
+		t.Fatalf("ApplyProfile() error = %v", err)
+	}
+
+	var changes []string
+	for _, call := range tools.calls(audio.SvclExe) {
+		if call != "/scomma" {
+			changes = append(changes, call)
+		}
+	}
+	want := []string{"/Disable Speakers", "/Enable Headphones", "/SetDefault Monitor all"}
+	if len(changes) != len(want) {
+		t.Fatalf("svcl calls = %q, want %q", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("svcl calls = %q, want %q", changes, want)
+			break
+		}
+	}
+}
+
+func TestSetPrimaryOutputDeviceNeedsActiveDevice(t *testing.T) {
+	app, tools := newTestApp(t)
+	app.storeAudioDevices([]AudioDevice{
+		{ID: "Speakers", Name: "Speakers", IsEnabled: true, State: audio.StateActive},
+		{ID: "TV", Name: "TV", IsEnabled: true, State: audio.StateUnplugged},
+	})
+
+	if err := app.SetPrimaryOutputDevice("TV"); err == nil {
+		t.Error("SetPrimaryOutputDevice() accepted an unplugged device")
+	}
+	if err := app.SetPrimaryOutputDevice("Speakers"); err != nil {
+		t.Fatalf("SetPrimaryOutputDevice() error = %v", err)
+	}
+	if !tools.called(audio.SvclExe, "/SetDefault Speakers all") || tools.called(audio.SvclExe, "/SetDefault TV all") {
+		t.Errorf("svcl calls = %q, want only Speakers set as default", tools.calls(audio.SvclExe))
+	}
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"monitor-profile-manager-wails/pkg/audio"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Automation triggers reported with auto-apply events
+const (
+	TriggerTopology          = "topology"
+	TriggerAudioConnected    = "audio-connected"
+	TriggerAudioDisconnected = "audio-disconnected"
+)
+
+// AutomationSettings holds the rules that apply profiles automatically
+type AutomationSettings struct {
+	Paused          bool           `json:"paused"`
+	DebounceSeconds int            `json:"debounceSeconds"` // wait for devices to settle before evaluating rules
+	TopologyRules   []TopologyRule `json:"topologyRules"`
+	AudioRules      []AudioRule    `json:"audioRules"`
+	Schedules       []ScheduleRule `json:"schedules"`
+	ProcessRules    []ProcessRule  `json:"processRules"`
+}
+
+// TopologyRule applies a profile when exactly this set of monitors is connected.
+// Monitors are short monitor IDs, repeated when several monitors of the same
+// model are connected.
+type TopologyRule struct {
+	Monitors []string `json:"monitors"`
+	Profile  string   `json:"profile"`
+}
+
+// AudioRule applies profiles when an audio device becomes available or goes away.
+// DeviceID is the svcl command-line ID, either profile may be left empty.
+type AudioRule struct {
+	DeviceID          string `json:"deviceId"`
+	ConnectProfile    string `json:"connectProfile"`
+	DisconnectProfile string `json:"disconnectProfile"`
+}
+
+// AutoApplyResult is the payload of the auto-apply event
+type AutoApplyResult struct {
+	Trigger string `json:"trigger"`
+	Profile string `json:"profile"`
+	Reason  string `json:"reason"`
+	Error   string `json:"error,omitempty"`
+}
+
+// debouncer runs a function once calls stopped arriving for the given delay
+type debouncer struct {
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+// trigger (re)starts the delay before fn runs
+func (d *debouncer) trigger(delay time.Duration, fn func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.timer = time.AfterFunc(delay, fn)
+}
+
+// stop cancels a pending run
+func (d *debouncer) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+}
+
+// automationState holds the runtime state of automatic profile application
+type automationState struct {
+	mu           sync.Mutex
+	topology     debouncer
+	lastTopology string
+	audio        debouncer
+	audioStates  map[string]bool // deviceID -> available at the last evaluation
+}
+
+// monitorTopology returns the sorted short monitor IDs of the connected monitors
+func monitorTopology(monitors []Monitor) []string {
+	topology := make([]string, 0, len(monitors))
+	for _, m := range monitors {
+		if m.IsConnected {
+			topology = append(topology, m.MonitorId)
+		}
+	}
+	sort.Strings(topology)
+	return topology
+}
+
+// topologyKey returns a comparable representation of a monitor set
+func topologyKey(monitorIds []string) string {
+	sorted := append([]string(nil), monitorIds...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// automationDebounce returns the configured debounce delay
+func (a *App) automationDebounce() time.Duration {
+	return time.Duration(a.currentSettings().Automation.DebounceSeconds) * time.Second
+}
+
+// handleTopologyEvent schedules a topology rule evaluation after monitor changes
+func (a *App) handleTopologyEvent(event Event) {
+	if !strings.HasPrefix(event.Type, "monitor:") {
+		return
+	}
+
+	automation := a.currentSettings().Automation
+	if automation.Paused || len(automation.TopologyRules) == 0 {
+		return
+	}
+
+	a.automation.topology.trigger(a.automationDebounce(), a.evaluateTopologyRules)
+}
+
+// seedTopology records the connected monitors so that topology rules only
+// react to later changes, not to the layout the app started with
+func (a *App) seedTopology(monitors []Monitor) {
+	key := topologyKey(monitorTopology(a.filterIgnoredMonitors(monitors)))
+
+	a.automation.mu.Lock()
+	defer a.automation.mu.Unlock()
+
+	a.automation.lastTopology = key
+}
+
+// evaluateTopologyRules applies the profile mapped to the connected monitors
+// when the set of connected monitors changed since the last evaluation
+func (a *App) evaluateTopologyRules() {
+	if a.currentSettings().Automation.Paused {
+		return
+	}
+
+	monitors, err := a.readMonitors()
+	if err != nil {
+		slog.Error("Topology automation failed", "error", err)
+		return
+	}
+
+	key := topologyKey(monitorTopology(a.filterIgnoredMonitors(monitors)))
+
+	a.automation.mu.Lock()
+	changed := key != a.automation.lastTopology
+	a.automation.lastTopology = key
+	a.automation.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	for _, rule := range a.currentSettings().Automation.TopologyRules {
+		if topologyKey(rule.Monitors) == key {
+			a.autoApplyProfile(TriggerTopology, rule.Profile, fmt.Sprintf("connected monitors: %s", key))
+			return
+		}
+	}
+}
+
+// autoApplyProfile applies a profile on behalf of an automation rule and reports the outcome
+func (a *App) autoApplyProfile(trigger string, profileName string, reason string) error {
+	result := AutoApplyResult{Trigger: trigger, Profile: profileName, Reason: reason}
+
+	err := a.applyProfileFrom(trigger, profileName)
+	if err != nil {
+		result.Error = err.Error()
+		slog.Error("Automation failed to apply profile", "trigger", trigger, "profile", profileName, "error", err)
+	}
+
+	a.emitEvent(EventProfileAutoApplied, result)
+	return err
+}
+
+// handleAudioRuleEvent schedules an audio rule evaluation after audio device changes
+func (a *App) handleAudioRuleEvent(event Event) {
+	if !strings.HasPrefix(event.Type, "audio:") {
+		return
+	}
+
+	automation := a.currentSettings().Automation
+	if automation.Paused || len(automation.AudioRules) == 0 {
+		return
+	}
+
+	a.automation.audio.trigger(a.automationDebounce(), a.evaluateAudioRules)
+}
+
+// audioAvailability maps every audio device ID to whether the device is active
+func audioAvailability(devices []AudioDevice) map[string]bool {
+	states := make(map[string]bool, len(devices))
+	for _, device := range devices {
+		states[device.ID] = device.State == audio.StateActive
+	}
+	return states
+}
+
+// seedAudioRuleStates records the current audio device availability so that
+// only later connects and disconnects trigger audio rules
+func (a *App) seedAudioRuleStates(devices []AudioDevice) {
+	a.automation.mu.Lock()
+	defer a.automation.mu.Unlock()
+
+	a.automation.audioStates = audioAvailability(devices)
+}
+
+// evaluateAudioRules applies the connect or disconnect profile of every rule
+// whose device changed availability since the last evaluation
+func (a *App) evaluateAudioRules() {
+	if a.currentSettings().Automation.Paused {
+		return
+	}
+
+	devices, err := a.readAudioDevices()
+	if err != nil {
+		slog.Error("Audio automation failed", "error", err)
+		return
+	}
+
+	current := audioAvailability(devices)
+
+	a.automation.mu.Lock()
+	previous := a.automation.audioStates
+	a.automation.audioStates = current
+	a.automation.mu.Unlock()
+
+	for _, rule := range a.currentSettings().Automation.AudioRules {
+		wasAvailable, isAvailable := previous[rule.DeviceID], current[rule.DeviceID]
+		if wasAvailable == isAvailable {
+			continue
+		}
+
+		if isAvailable && rule.ConnectProfile != "" {
+			a.autoApplyProfile(TriggerAudioConnected, rule.ConnectProfile, fmt.Sprintf("audio device connected: %s", rule.DeviceID))
+		} else if !isAvailable && rule.DisconnectProfile != "" {
+			a.autoApplyProfile(TriggerAudioDisconnected, rule.DisconnectProfile, fmt.Sprintf("audio device disconnected: %s", rule.DeviceID))
+		}
+	}
+}
+
+// GetCurrentTopology returns the short monitor IDs of the connected, non ignored monitors
+func (a *App) GetCurrentTopology() ([]string, error) {
+	monitors, err := a.readMonitors()
+	if err != nil {
+		return nil, err
+	}
+	return monitorTopology(a.filterIgnoredMonitors(monitors)), nil
+}
+
+// GetTopologyRules returns the configured topology rules
+func (a *App) GetTopologyRules() []TopologyRule {
+	return a.currentSettings().Automation.TopologyRules
+}
+
+// AddTopologyRule validates and stores a new topology rule
+func (a *App) AddTopologyRule(rule TopologyRule) error {
+	if len(rule.Monitors) == 0 {
+		return fmt.Errorf("topology rule needs at least one monitor")
+	}
+
+	if a.findProfile(rule.Profile) == nil {
+		return fmt.Errorf("profile not found: %s", rule.Profile)
+	}
+
+	key := topologyKey(rule.Monitors)
+	return a.updateSettings(func(s *Settings) error {
+		for _, existing := range s.Automation.TopologyRules {
+			if topologyKey(existing.Monitors) == key {
+				return fmt.Errorf("a topology rule for these monitors already exists (profile %s)", existing.Profile)
+			}
+		}
+
+		s.Automation.TopologyRules = append(s.Automation.TopologyRules, rule)
+		return nil
+	})
+}
+
+// RemoveTopologyRule removes the rule matching the given monitor set
+func (a *App) RemoveTopologyRule(monitors []string) error {
+	key := topologyKey(monitors)
+	return a.updateSettings(func(s *Settings) error {
+		for i, existing := range s.Automation.TopologyRules {
+			if topologyKey(existing.Monitors) == key {
+				s.Automation.TopologyRules = append(s.Automation.TopologyRules[:i], s.Automation.TopologyRules[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("topology rule not found")
+	})
+}
+
+// GetAudioRules returns the configured audio device rules
+func (a *App) GetAudioRules() []AudioRule {
+	return a.currentSettings().Automation.AudioRules
+}
+
+// AddAudioRule validates and stores a new audio device rule
+func (a *App) AddAudioRule(rule AudioRule) error {
+	if rule.DeviceID == "" {
+		return fmt.Errorf("audio rule needs a device")
+	}
+
+	if rule.ConnectProfile == "" && rule.DisconnectProfile == "" {
+		return fmt.Errorf("audio rule needs a connect or disconnect profile")
+	}
+
+	for _, profileName := range []string{rule.ConnectProfile, rule.DisconnectProfile} {
+		if profileName != "" && a.findProfile(profileName) == nil {
+			return fmt.Errorf("profile not found: %s", profileName)
+		}
+	}
+
+	return a.updateSettings(func(s *Settings) error {
+		for _, existing := range s.Automation.AudioRules {
+			if existing.DeviceID == rule.DeviceID {
+				return fmt.Errorf("an audio rule for this device already exists")
+			}
+		}
+
+		s.Automation.AudioRules = append(s.Automation.AudioRules, rule)
+		return nil
+	})
+}
+
+// RemoveAudioRule removes the rule for the given audio device
+func (a *App) RemoveAudioRule(deviceID string) error {
+	return a.updateSettings(func(s *Settings) error {
+		for i, existing := range s.Automation.AudioRules {
+			if existing.DeviceID == deviceID {
+				s.Automation.AudioRules = append(s.Automation.AudioRules[:i], s.Automation.AudioRules[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("audio rule not found")
+	})
+}
+
+// IsAutomationPaused reports whether automatic profile application is paused
+func (a *App) IsAutomationPaused() bool {
+	return a.currentSettings().Automation.Paused
+}
+
+// SetAutomationPaused pauses or resumes automatic profile application
+func (a *App) SetAutomationPaused(paused bool) error {
+	err := a.updateSettings(func(s *Settings) error {
+		s.Automation.Paused = paused
+		return nil
+	})
+	if paused {
+		a.automation.topology.stop()
+		a.automation.audio.stop()
+	}
+	if err != nil {
+		return err
+	}
+
+	sendAutomationPaused(paused)
+	return nil
+}
+
+// sendAutomationPaused passes the automation state to the tray without
+// blocking. A state the tray has not read yet is replaced, so callers never
+// wait for a tray that has not started or never will.
+func sendAutomationPaused(paused bool) {
+	for {
+		select {
+		case automationPausedCh <- paused:
+			return
+		default:
+		}
+
+		select {
+		case <-automationPausedCh:
+		default:
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTopologyRulesIgnoreStartupLayout(t *testing.T) {
+	app, tools := newTestApp(t)
+	laptop := fakeMonitor{name: `\\.\DISPLAY1`, id: "LAP0001", displayName: "Laptop", active: true, primary: true}
+	dock := fakeMonitor{name: `\\.\DISPLAY2`, id: "DEL4242", displayName: "Dell", active: true}
+	tools.setMonitors(laptop, dock)
+
+	for _, name := range []string{"Docked", "Mobile"} {
+		if err := app.saveCurrentProfile(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rules := []TopologyRule{
+		{Monitors: []string{"DEL4242", "LAP0001"}, Profile: "Docked"},
+		{Monitors: []string{"LAP0001"}, Profile: "Mobile"},
+	}
+	for _, rule := range rules {
+		if err := app.AddTopologyRule(rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app.loadMonitors()
+	app.seedTopology(app.currentMonitors())
+	tools.resetCalls()
+
+	// Starting up docked must not re-apply the docked profile over the layout
+	// the user already has
+	app.evaluateTopologyRules()
+	if tools.called("MultiMonitorTool.exe", "/LoadConfig "+app.getMonitorConfigPath("Docked")) {
+		t.Fatalf("startup layout applied a profile: %v", tools.calls("MultiMonitorTool.exe"))
+	}
+
+	// Undocking afterwards applies the matching rule
+	dock.disconnected = true
+	tools.setMonitors(laptop, dock)
+	app.evaluateTopologyRules()
+	if !tools.called("MultiMonitorTool.exe", "/LoadConfig "+app.getMonitorConfigPath("Mobile")) {
+		t.Errorf("undocking did not apply Mobile: %v", tools.calls("MultiMonitorTool.exe"))
+	}
+}
+
+func TestSetAutomationPausedWithoutTray(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, paused := range []bool{true, false, true} {
+			if err := app.SetAutomationPaused(paused); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	waitFor(t, 5*time.Second, "SetAutomationPaused to return without a tray", func() bool {
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	})
+
+	if paused := <-automationPausedCh; !paused {
+		t.Error("tray state = running, want the latest state paused")
+	}
+}
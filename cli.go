@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// Exit codes of the command line interface
+const (
+	EXIT_OK        = 0
+	EXIT_ERROR     = 1 // the operation failed
+	EXIT_USAGE     = 2 // invalid arguments
+	EXIT_NOT_FOUND = 3 // the named profile, monitor or audio device does not exist
+)
+
+// cliError carries the exit code of a failed command
+type cliError struct {
+	code int
+	err  error
+}
+
+func (e *cliError) Error() string {
+	return e.err.Error()
+}
+
+func usageError(format string, args ...interface{}) error {
+	return &cliError{code: EXIT_USAGE, err: fmt.Errorf(format, args...)}
+}
+
+func notFoundError(format string, args ...interface{}) error {
+	return &cliError{code: EXIT_NOT_FOUND, err: fmt.Errorf(format, args...)}
+}
+
+// cliStatus is the JSON output of commands that do not return data
+type cliStatus struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// cliCommand is a headless subcommand
+type cliCommand struct {
+	name        string
+	args        []string // names of the required positional arguments
+	description string
+	run         func(a *App, args []string) (interface{}, error)
+	print       func(w io.Writer, result interface{}) // text output, nil prints nothing
+}
+
+var cliCommands = []cliCommand{
+	{name: "list-monitors", description: "List connected monitors", run: cliListMonitors, print: printMonitors},
+	{name: "list-audio", description: "List audio output devices", run: cliListAudio, print: printAudioDevices},
+	{name: "list-profiles", description: "List saved profiles", run: cliListProfiles, print: printProfiles},
+	{name: "list-tools", description: "List the external tools in use with their versions", run: cliListTools, print: printTools},
+	{name: "save", args: []string{"name"}, description: "Save the current setup as a new profile", run: cliSaveProfile},
+	{name: "apply", args: []string{"name"}, description: "Apply a profile", run: cliApplyProfile},
+	{name: "delete", args: []string{"name"}, description: "Delete a profile", run: cliDeleteProfile},
+	{name: "set-primary", args: []string{"monitor"}, description: "Make a monitor the primary display (ID, device name or nickname)", run: cliSetPrimary},
+	{name: "set-audio-default", args: []string{"device"}, description: "Make an audio device the default output (ID, name or nickname)", run: cliSetAudioDefault},
+}
+
+// findCLICommand returns the subcommand with the given name or nil
+func findCLICommand(name string) *cliCommand {
+	for i := range cliCommands {
+		if cliCommands[i].name == name {
+			return &cliCommands[i]
+		}
+	}
+	return nil
+}
+
+// printCLIUsage lists the available subcommands
+func printCLIUsage(w io.Writer) {
+	fmt.Fprintln(w, "Subcommands (add --json anywhere for JSON output):")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, command := range cliCommands {
+		fmt.Fprintf(tw, "  %s\t%s\n", command.usage(), command.description)
+	}
+	tw.Flush()
+}
+
+// parseCLIArgs parses the flags of a subcommand wherever they appear between
+// the positional arguments, flag.Parse alone stops at the first positional
+// argument. Everything after "--" is positional.
+func parseCLIArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+
+		rest := flags.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+
+		// Parse consumed a "--" terminator right before the remaining arguments
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// runCLI runs a headless subcommand without starting Wails or the tray and
+// returns the process exit code
+func (a *App) runCLI(command *cliCommand, args []string) int {
+	flags := flag.NewFlagSet(command.name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	jsonOutput := flags.Bool("json", false, "Print JSON output")
+
+	result, err := func() (interface{}, error) {
+		positional, err := parseCLIArgs(flags, args)
+		if err != nil {
+			return nil, usageError("%s: %v", command.name, err)
+		}
+		if len(positional) != len(command.args) {
+			return nil, usageError("usage: %s", command.usage())
+		}
+
+		defer a.removeToolsTempDir()
+		if err := a.initHeadless(); err != nil {
+			return nil, err
+		}
+		defer a.webhooks.Drain(WEBHOOK_DRAIN_TIMEOUT)
+
+		return command.run(a, positional)
+	}()
+
+	code := EXIT_OK
+	if err != nil {
+		code = EXIT_ERROR
+		var cErr *cliError
+		if errors.As(err, &cErr) {
+			code = cErr.code
+		}
+	}
+
+	if *jsonOutput {
+		if err != nil {
+			result = cliStatus{OK: false, Error: err.Error()}
+		} else if result == nil {
+			result = cliStatus{OK: true}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(result)
+		return code
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if code == EXIT_USAGE {
+			printCLIUsage(os.Stderr)
+		}
+		return code
+	}
+
+	if command.print != nil {
+		command.print(os.Stdout, result)
+	}
+	return code
+}
+
+// usage returns the command with its positional arguments, e.g. "apply <name>"
+func (c *cliCommand) usage() string {
+	parts := []string{c.name}
+	for _, arg := range c.args {
+		parts = append(parts, "<"+arg+">")
+	}
+	return strings.Join(parts, " ")
+}
+
+// commandTrigger returns the apply history trigger of a subcommand. The
+// subcommands also serve the WebSocket command channel, which runs inside the
+// GUI process.
+func (a *App) commandTrigger() string {
+	if a.headless {
+		return TriggerCLI
+	}
+	return TriggerWebSocket
+}
+
+func cliListMonitors(a *App, args []string) (interface{}, error) {
+	monitors, err := a.readMonitors()
+	if err != nil {
+		return nil, err
+	}
+	return a.filterIgnoredMonitors(monitors), nil
+}
+
+func cliListAudio(a *App, args []string) (interface{}, error) {
+	return a.readVisibleAudioDevices()
+}
+
+func cliListProfiles(a *App, args []string) (interface{}, error) {
+	return a.currentProfiles(), nil
+}
+
+func cliListTools(a *App, args []string) (interface{}, error) {
+	return a.GetToolInfo(), nil
+}
+
+func cliSaveProfile(a *App, args []string) (interface{}, error) {
+	return nil, a.saveCurrentProfile(args[0])
+}
+
+func cliApplyProfile(a *App, args []string) (interface{}, error) {
+	if a.findProfile(args[0]) == nil {
+		return nil, notFoundError("profile not found: %s", args[0])
+	}
+	return nil, a.applyProfileFrom(a.commandTrigger(), args[0])
+}
+
+func cliDeleteProfile(a *App, args []string) (interface{}, error) {
+	if a.findProfile(args[0]) == nil {
+		return nil, notFoundError("profile not found: %s", args[0])
+	}
+	return nil, a.DeleteProfile(args[0])
+}
+
+func cliSetPrimary(a *App, args []string) (interface{}, error) {
+	monitors, err := a.readMonitors()
+	if err != nil {
+		return nil, err
+	}
+	a.storeMonitors(monitors)
+
+	for _, monitor := range monitors {
+		if args[0] == monitor.MonitorId || args[0] == monitor.DeviceName || (monitor.Nickname != "" && args[0] == monitor.Nickname) {
+			return nil, a.SetMonitorPrimary(monitor.MonitorId)
+		}
+	}
+	return nil, notFoundError("monitor not found: %s", args[0])
+}
+
+func cliSetAudioDefault(a *App, args []string) (interface{}, error) {
+	devices, err := a.readAudioDevices()
+	if err != nil {
+		return nil, err
+	}
+	a.storeAudioDevices(devices)
+
+	for _, device := range devices {
+		if args[0] == device.ID || args[0] == device.Name || (device.Nickname != "" && args[0] == device.Nickname) {
+			return nil, a.SetPrimaryOutputDevice(device.ID)
+		}
+	}
+	return nil, notFoundError("audio device not found: %s", args[0])
+}
+
+func printMonitors(w io.Writer, result interface{}) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tDEVICE\tNAME\tNICKNAME\tACTIVE\tPRIMARY")
+	for _, monitor := range result.([]Monitor) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\t%t\n", monitor.MonitorId, monitor.DeviceName, monitor.DisplayName, monitor.Nickname, monitor.IsActive, monitor.IsPrimary)
+	}
+	tw.Flush()
+}
+
+func printAudioDevices(w io.Writer, result interface{}) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tNICKNAME\tSTATE\tDEFAULT")
+	for _, device := range result.([]AudioDevice) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\n", device.ID, device.Name, device.Nickname, device.State, device.IsDefault)
+	}
+	tw.Flush()
+}
+
+func printProfiles(w io.Writer, result interface{}) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tMONITORS\tHOTKEY")
+	for _, profile := range result.([]Profile) {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", profile.Name, len(profile.Monitors), profile.Hotkey)
+	}
+	tw.Flush()
+}
+
+func printTools(w io.Writer, result interface{}) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOOL\tVERSION\tSOURCE\tPATH\tERROR")
+	for _, tool := range result.([]ToolInfo) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", tool.Tool, tool.Version, tool.Source, tool.Path, tool.Error)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseCLIArgs(t *testing.T) {
+	tests := []struct {
+		args       []string
+		positional []string
+		json       bool
+	}{
+		{[]string{"Desk"}, []string{"Desk"}, false},
+		{[]string{"--json", "Desk"}, []string{"Desk"}, true},
+		{[]string{"Desk", "--json"}, []string{"Desk"}, true},
+		{[]string{"-json", "Desk"}, []string{"Desk"}, true},
+		{[]string{"a", "--json", "b"}, []string{"a", "b"}, true},
+		{[]string{"--json"}, nil, true},
+		{[]string{"--", "--json"}, []string{"--json"}, false},
+		{[]string{"Desk", "--", "-x"}, []string{"Desk", "-x"}, false},
+		{[]string{"--json", "--", "-Desk"}, []string{"-Desk"}, true},
+	}
+
+	for _, tt := range tests {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		jsonOutput := flags.Bool("json", false, "")
+
+		positional, err := parseCLIArgs(flags, tt.args)
+		if err != nil {
+			t.Errorf("parseCLIArgs(%q) error = %v", tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(positional, tt.positional) || *jsonOutput != tt.json {
+			t.Errorf("parseCLIArgs(%q) = %q, json %v, want %q, json %v", tt.args, positional, *jsonOutput, tt.positional, tt.json)
+		}
+	}
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	if _, err := parseCLIArgs(flags, []string{"Desk", "--verbose"}); err == nil {
+		t.Error("parseCLIArgs() accepted an unknown flag after a positional argument")
+	}
+}
+
+// runCLICapture runs a subcommand and returns its exit code and standard output
+func runCLICapture(t *testing.T, app *App, name string, args ...string) (int, string) {
+	t.Helper()
+
+	command := findCLICommand(name)
+	if command == nil {
+		t.Fatalf("unknown command %s", name)
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		output <- string(data)
+	}()
+
+	code := app.runCLI(command, args)
+	writer.Close()
+	return code, <-output
+}
+
+func TestRunCLIJSONAfterArgumentsAndDuplicateSave(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	code, output := runCLICapture(t, app, "save", "Desk", "--json")
+	var status cliStatus
+	if err := json.Unmarshal([]byte(output), &status); err != nil {
+		t.Fatalf("save output %q is not JSON: %v", output, err)
+	}
+	if code != EXIT_OK || !status.OK {
+		t.Fatalf("save = %d, %+v, want success", code, status)
+	}
+
+	code, output = runCLICapture(t, app, "save", "--json", "Desk")
+	status = cliStatus{}
+	if err := json.Unmarshal([]byte(output), &status); err != nil {
+		t.Fatalf("save output %q is not JSON: %v", output, err)
+	}
+	if code != EXIT_ERROR || status.OK || status.Error != "profile already exists: Desk" {
+		t.Errorf("duplicate save = %d, %+v, want the profile already exists error", code, status)
+	}
+
+	if profiles := app.currentProfiles(); len(profiles) != 1 {
+		t.Errorf("profiles = %+v, want a single Desk", profiles)
+	}
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+const ATTACH_PARENT_PROCESS = ^uint32(0)
+
+var procAttachConsole = windows.NewLazySystemDLL("kernel32.dll").NewProc("AttachConsole")
+
+// attachConsole connects stdout and stderr to the console of the parent process.
+// The app is built as a GUI program, so command line output is lost otherwise.
+// Redirected output, e.g. over SSH or in CI, is left alone.
+func attachConsole() {
+	if handle, err := windows.GetStdHandle(windows.STD_OUTPUT_HANDLE); err == nil && handle != 0 && handle != windows.InvalidHandle {
+		return
+	}
+
+	if ret, _, _ := procAttachConsole.Call(uintptr(ATTACH_PARENT_PROCESS)); ret == 0 {
+		return
+	}
+
+	if console, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0); err == nil {
+		os.Stdout = console
+		os.Stderr = console
+	}
+}
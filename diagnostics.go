@@ -0,0 +1,315 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"monitor-profile-manager-wails/pkg/integrity"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	DIAGNOSTICS_REDACTED     = "REDACTED"
+	COL_MONITOR_SERIAL       = "Monitor Serial Number"
+	DIAGNOSTICS_FILE_PATTERN = "windows-profile-manager-diagnostics-%s.zip"
+	DIAGNOSTICS_MONITOR_LIST = "tools/multimonitortool-list.csv"
+
+	// DIAGNOSTICS_MIN_SERIAL_LENGTH is the shortest serial number replaced
+	// outside the serial column of the monitor list
+	DIAGNOSTICS_MIN_SERIAL_LENGTH = 6
+)
+
+// version is set at build time with -ldflags "-X main.version=1.2.0"
+var version = "dev"
+
+// DiagnosticsInfo is the info.json of a diagnostics archive
+type DiagnosticsInfo struct {
+	Version    string            `json:"version"`
+	Revision   string            `json:"revision,omitempty"` // VCS revision the binary was built from
+	GoVersion  string            `json:"goVersion"`
+	OS         string            `json:"os"`
+	Arch       string            `json:"arch"`
+	Time       time.Time         `json:"time"`
+	ToolHashes map[string]string `json:"toolHashes"` // SHA-256 of every embedded tool
+	Tools      []ToolInfo        `json:"tools"`      // the tools in use with their versions
+	Redacted   bool              `json:"redacted"`
+	Errors     []string          `json:"errors,omitempty"` // parts that could not be collected
+}
+
+// diagnosticsFile is a file added to the archive
+type diagnosticsFile struct {
+	name string
+	data []byte
+}
+
+// embeddedToolHashes returns the hex encoded SHA-256 of every embedded tool
+func embeddedToolHashes() map[string]string {
+	hashes := make(map[string]string, len(embeddedTools))
+	for _, tool := range embeddedTools {
+		data, err := assets.ReadFile(tool.srcPath)
+		if err != nil {
+			hashes[tool.srcPath] = "missing"
+			continue
+		}
+		hashes[tool.srcPath] = integrity.HashBytes(data)
+	}
+	return hashes
+}
+
+// buildRevision returns the VCS revision embedded by the Go toolchain
+func buildRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return ""
+}
+
+// serialColumn returns the index of the serial number column in MultiMonitorTool
+// CSV records or -1
+func serialColumn(records [][]string) int {
+	if len(records) == 0 {
+		return -1
+	}
+	for i, name := range records[0] {
+		if strings.TrimSpace(name) == COL_MONITOR_SERIAL {
+			return i
+		}
+	}
+	return -1
+}
+
+// monitorSerials returns the serial numbers in MultiMonitorTool CSV output
+func monitorSerials(monitorList []byte) []string {
+	records, err := csv.NewReader(bytes.NewReader(monitorList)).ReadAll()
+	if err != nil {
+		return nil
+	}
+
+	column := serialColumn(records)
+	if column < 0 {
+		return nil
+	}
+
+	var serials []string
+	for _, row := range records[1:] {
+		if column < len(row) {
+			if serial := strings.TrimSpace(row[column]); serial != "" {
+				serials = append(serials, serial)
+			}
+		}
+	}
+	return serials
+}
+
+// redactSerialColumn replaces every value of the serial number column in
+// MultiMonitorTool CSV output. Output that cannot be parsed is returned as is.
+func redactSerialColumn(monitorList []byte) []byte {
+	records, err := csv.NewReader(bytes.NewReader(monitorList)).ReadAll()
+	if err != nil {
+		return monitorList
+	}
+
+	column := serialColumn(records)
+	if column < 0 {
+		return monitorList
+	}
+
+	for _, row := range records[1:] {
+		if column < len(row) && strings.TrimSpace(row[column]) != "" {
+			row[column] = DIAGNOSTICS_REDACTED
+		}
+	}
+
+	var out bytes.Buffer
+	writer := csv.NewWriter(&out)
+	if err := writer.WriteAll(records); err != nil {
+		return monitorList
+	}
+	return out.Bytes()
+}
+
+// redactDiagnostics replaces the monitor serial numbers in the archive files.
+// The serial column of the monitor list is always replaced. Elsewhere only
+// serials of at least DIAGNOSTICS_MIN_SERIAL_LENGTH characters are, shorter
+// values such as "0" would match unrelated text.
+func redactDiagnostics(files []diagnosticsFile, serials []string) {
+	for i := range files {
+		if files[i].name == DIAGNOSTICS_MONITOR_LIST {
+			files[i].data = redactSerialColumn(files[i].data)
+		}
+		for _, serial := range serials {
+			if len(serial) >= DIAGNOSTICS_MIN_SERIAL_LENGTH {
+				files[i].data = bytes.ReplaceAll(files[i].data, []byte(serial), []byte(DIAGNOSTICS_REDACTED))
+			}
+		}
+	}
+}
+
+// collectDiagnostics gathers the archive contents. Parts that cannot be
+// collected are reported in info.Errors instead of failing the export.
+func (a *App) collectDiagnostics(info *DiagnosticsInfo) ([]diagnosticsFile, []string) {
+	var files []diagnosticsFile
+	var serials []string
+
+	addFile := func(name string, sourcePath string) {
+		data, err := os.ReadFile(sourcePath)
+		if os.IsNotExist(err) {
+			return
+		}
+		if err != nil {
+			info.Errors = append(info.Errors, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		files = append(files, diagnosticsFile{name: name, data: data})
+	}
+	addJSON := func(name string, value interface{}) {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			info.Errors = append(info.Errors, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		files = append(files, diagnosticsFile{name: name, data: data})
+	}
+
+	// Raw tool output
+	if a.monitorTools != nil {
+		if monitorList, err := a.monitorTools.GetMonitorListCSV(); err != nil {
+			info.Errors = append(info.Errors, fmt.Sprintf("MultiMonitorTool /List: %v", err))
+		} else {
+			files = append(files, diagnosticsFile{name: DIAGNOSTICS_MONITOR_LIST, data: monitorList})
+			serials = monitorSerials(monitorList)
+		}
+	}
+	if a.audioTools != nil {
+		if deviceList, err := a.audioTools.GetOutputDevicesCSV(); err != nil {
+			info.Errors = append(info.Errors, fmt.Sprintf("svcl /scomma: %v", err))
+		} else {
+			files = append(files, diagnosticsFile{name: "tools/svcl-scomma.csv", data: deviceList})
+		}
+	}
+
+	// Profiles and their monitor configurations
+	profilesDir := a.getProfilesDir()
+	addFile("profiles/"+PROFILE_FILE_NAME, filepath.Join(profilesDir, PROFILE_FILE_NAME))
+	configs, _ := filepath.Glob(filepath.Join(profilesDir, "*.cfg"))
+	for _, config := range configs {
+		addFile("profiles/"+filepath.Base(config), config)
+	}
+
+	addFile(IGNORE_LIST_FILE_NAME, a.getIgnoreListPath())
+	addJSON("nicknames.json", a.currentNicknames())
+	addFile(APPLY_HISTORY_FILE_NAME, a.getApplyHistoryPath())
+
+	// Logs and the tool audit trail, including rotated files
+	for _, logFile := range a.logs.Files() {
+		addFile("logs/"+filepath.Base(logFile), logFile)
+	}
+	if a.toolAuditFile != nil {
+		for _, auditFile := range a.toolAuditFile.Files() {
+			addFile("logs/"+filepath.Base(auditFile), auditFile)
+		}
+	}
+
+	return files, serials
+}
+
+// ExportDiagnostics writes a zip archive for bug reports with version
+// information, raw tool output, profiles, monitor configurations, ignore list,
+// nicknames, logs and apply history. With redactSerials, monitor serial
+// numbers are replaced as described at redactDiagnostics. Without a path the
+// user picks one, the path written to is returned and is empty when the
+// dialog was cancelled.
+func (a *App) ExportDiagnostics(archivePath string, redactSerials bool) (string, error) {
+	if archivePath == "" {
+		if a.ctx == nil {
+			return "", fmt.Errorf("no path given for the diagnostics archive")
+		}
+
+		var err error
+		archivePath, err = wailsRuntime.SaveFileDialog(a.ctx, wailsRuntime.SaveDialogOptions{
+			Title:           "Export diagnostics",
+			DefaultFilename: fmt.Sprintf(DIAGNOSTICS_FILE_PATTERN, time.Now().Format("20060102-150405")),
+			Filters:         []wailsRuntime.FileFilter{{DisplayName: "Zip archives (*.zip)", Pattern: "*.zip"}},
+		})
+		if err != nil || archivePath == "" {
+			return "", err
+		}
+	}
+
+	info := DiagnosticsInfo{
+		Version:    version,
+		Revision:   buildRevision(),
+		GoVersion:  runtime.Version(),
+		OS:         osVersion(),
+		Arch:       runtime.GOARCH,
+		Time:       time.Now(),
+		ToolHashes: embeddedToolHashes(),
+		Tools:      a.GetToolInfo(),
+		Redacted:   redactSerials,
+	}
+
+	files, serials := a.collectDiagnostics(&info)
+
+	infoData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal diagnostics info: %v", err)
+	}
+	files = append([]diagnosticsFile{{name: "info.json", data: infoData}}, files...)
+
+	if redactSerials {
+		redactDiagnostics(files, serials)
+	}
+
+	if err := writeZip(archivePath, files); err != nil {
+		return "", err
+	}
+
+	return archivePath, nil
+}
+
+// writeZip writes the files to a new zip archive at archivePath
+func writeZip(archivePath string, files []diagnosticsFile) error {
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create diagnostics archive: %v", err)
+	}
+
+	archive := zip.NewWriter(out)
+	for _, file := range files {
+		writer, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     path.Clean(file.name),
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err == nil {
+			_, err = writer.Write(file.data)
+		}
+		if err != nil {
+			out.Close()
+			return fmt.Errorf("failed to write %s to diagnostics archive: %v", file.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write diagnostics archive: %v", err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactDiagnostics(t *testing.T) {
+	monitorList := "Name,Monitor Serial Number,Monitor Name\n" +
+		"\\\\.\\DISPLAY1,0,DELL U2720Q\n" +
+		"\\\\.\\DISPLAY2,ABC12345,LG 27GL850\n" +
+		"\\\\.\\DISPLAY3,,Generic\n"
+	files := []diagnosticsFile{
+		{name: DIAGNOSTICS_MONITOR_LIST, data: []byte(monitorList)},
+		{name: "profiles/Desk.cfg", data: []byte("SerialNumber=ABC12345\nPositionX=0\nWidth=2560\n")},
+		{name: "logs/app.log", data: []byte("time=2026-10-18T09:00:00 msg=applied monitors=2 serial=ABC12345\n")},
+	}
+
+	serials := monitorSerials([]byte(monitorList))
+	if strings.Join(serials, ",") != "0,ABC12345" {
+		t.Fatalf("monitorSerials() = %q, want 0 and ABC12345", serials)
+	}
+
+	redactDiagnostics(files, serials)
+
+	wantList := "Name,Monitor Serial Number,Monitor Name\n" +
+		"\\\\.\\DISPLAY1,REDACTED,DELL U2720Q\n" +
+		"\\\\.\\DISPLAY2,REDACTED,LG 27GL850\n" +
+		"\\\\.\\DISPLAY3,,Generic\n"
+	if got := string(files[0].data); got != wantList {
+		t.Errorf("monitor list = %q, want %q", got, wantList)
+	}
+
+	// The short serial "0" is left alone outside the serial column
+	if got, want := string(files[1].data), "SerialNumber=REDACTED\nPositionX=0\nWidth=2560\n"; got != want {
+		t.Errorf("monitor config = %q, want %q", got, want)
+	}
+	if got, want := string(files[2].data), "time=2026-10-18T09:00:00 msg=applied monitors=2 serial=REDACTED\n"; got != want {
+		t.Errorf("log = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"monitor-profile-manager-wails/pkg/audio"
+	"monitor-profile-manager-wails/pkg/monitors"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MAX_DRIFT_CORRECTIONS = 100
+)
+
+// Drift domains
+const (
+	DriftDomainMonitors = "monitors"
+	DriftDomainAudio    = "audio"
+)
+
+// EnforceSettings configures drift enforcement for the last applied profile
+type EnforceSettings struct {
+	Enabled            bool `json:"enabled"`
+	GracePeriodSeconds int  `json:"gracePeriodSeconds"` // wait after a device change before checking for drift
+	MinIntervalSeconds int  `json:"minIntervalSeconds"` // minimum time between two corrections of the same domain
+}
+
+// DriftCorrection records one re-application of a drifted part of the active profile
+type DriftCorrection struct {
+	Time    time.Time `json:"time"`
+	Profile string    `json:"profile"`
+	Domain  string    `json:"domain"`
+	Details string    `json:"details"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// driftEnforcer holds the runtime state of drift enforcement
+type driftEnforcer struct {
+	check debouncer // waits for the grace period after device changes
+
+	mu             sync.Mutex
+	lastCorrection map[string]time.Time
+	corrections    []DriftCorrection
+}
+
+func newDriftEnforcer() *driftEnforcer {
+	return &driftEnforcer{
+		lastCorrection: make(map[string]time.Time),
+		corrections:    []DriftCorrection{},
+	}
+}
+
+// record appends a correction, keeping only the most recent ones
+func (e *driftEnforcer) record(correction DriftCorrection) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.corrections = append(e.corrections, correction)
+	if len(e.corrections) > MAX_DRIFT_CORRECTIONS {
+		e.corrections = e.corrections[len(e.corrections)-MAX_DRIFT_CORRECTIONS:]
+	}
+}
+
+// allow reports whether the domain may be corrected now and reserves the slot
+func (e *driftEnforcer) allow(domain string, minInterval time.Duration, now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if last, ok := e.lastCorrection[domain]; ok && now.Sub(last) < minInterval {
+		return false
+	}
+
+	e.lastCorrection[domain] = now
+	return true
+}
+
+// handleEnforcementEvent schedules a drift check after every device change
+func (a *App) handleEnforcementEvent(event Event) {
+	if !strings.HasPrefix(event.Type, "monitor:") && !strings.HasPrefix(event.Type, "audio:") {
+		return
+	}
+
+	settings := a.currentSettings()
+	if !settings.Enforce.Enabled || settings.LastAppliedProfile == "" {
+		return
+	}
+
+	grace := time.Duration(settings.Enforce.GracePeriodSeconds) * time.Second
+	a.enforcer.check.trigger(grace, a.enforceActiveProfile)
+}
+
+// enforceActiveProfile re-applies every drifted part of the last applied profile
+func (a *App) enforceActiveProfile() {
+	if !a.currentSettings().Enforce.Enabled {
+		return
+	}
+
+	applyMutex.Lock()
+	defer applyMutex.Unlock()
+
+	// Read the settings after taking the apply lock so a profile applied in
+	// the meantime is the one enforced
+	settings := a.currentSettings()
+	profile := a.findProfile(settings.LastAppliedProfile)
+	if profile == nil {
+		return
+	}
+
+	minInterval := time.Duration(settings.Enforce.MinIntervalSeconds) * time.Second
+
+	if details := a.detectMonitorDrift(*profile); details != "" && a.enforcer.allow(DriftDomainMonitors, minInterval, time.Now()) {
+		err := a.monitorTools.ApplyMonitorConfig(a.getMonitorConfigPath(profile.Name))
+		a.recordDriftCorrection(profile.Name, DriftDomainMonitors, details, err)
+	}
+
+	if details := a.detectAudioDrift(*profile); details != "" && a.enforcer.allow(DriftDomainAudio, minInterval, time.Now()) {
+		err := a.correctAudioDrift(*profile)
+		a.recordDriftCorrection(profile.Name, DriftDomainAudio, details, err)
+	}
+}
+
+// detectMonitorDrift compares the current monitors with the layout saved in the
+// profile and describes the differences. Monitors that are not connected or
+// were not part of the profile are skipped.
+func (a *App) detectMonitorDrift(profile Profile) string {
+	if len(profile.Monitors) == 0 {
+		return ""
+	}
+
+	current, err := a.readMonitors()
+	if err != nil {
+		return ""
+	}
+
+	// Without the saved configuration only the active and primary flags are compared
+	layouts, err := monitors.ReadConfigLayouts(a.getMonitorConfigPath(profile.Name))
+	if err != nil {
+		slog.Warn("Failed to read saved monitor layout", "profile", profile.Name, "error", err)
+	}
+
+	currentByKey := make(map[string]Monitor, len(current))
+	for _, m := range current {
+		currentByKey[monitorKey(m)] = m
+	}
+
+	var drifts []string
+	for _, saved := range profile.Monitors {
+		m, ok := currentByKey[monitorKey(saved)]
+		if !ok {
+			continue
+		}
+
+		if m.IsActive != saved.IsActive {
+			drifts = append(drifts, fmt.Sprintf("%s active %t, expected %t", m.DeviceName, m.IsActive, saved.IsActive))
+		}
+		if m.IsPrimary != saved.IsPrimary {
+			drifts = append(drifts, fmt.Sprintf("%s primary %t, expected %t", m.DeviceName, m.IsPrimary, saved.IsPrimary))
+		}
+		if expected, ok := layouts[m.DeviceName]; ok && m.IsActive && m.layout != nil && !m.layout.Matches(expected) {
+			drifts = append(drifts, fmt.Sprintf("%s layout %s, expected %s", m.DeviceName, m.layout, expected))
+		}
+	}
+
+	return strings.Join(drifts, "; ")
+}
+
+// detectAudioDrift checks whether the audio device states or the default
+// output device differ from the profile. Devices that are not available are
+// skipped.
+func (a *App) detectAudioDrift(profile Profile) string {
+	if profile.Audio.DefaultOutputDeviceId == "" && len(profile.Audio.DeviceStates) == 0 {
+		return ""
+	}
+
+	devices, err := a.readAudioDevices()
+	if err != nil {
+		return ""
+	}
+
+	var drifts []string
+	for _, device := range devices {
+		enabled, ok := profile.Audio.DeviceStates[device.ID]
+		if ok && device.isPresent() && device.IsEnabled != enabled {
+			drifts = append(drifts, fmt.Sprintf("%s enabled %t, expected %t", device.Name, device.IsEnabled, enabled))
+		}
+	}
+	if details := defaultAudioDrift(profile, devices); details != "" {
+		drifts = append(drifts, details)
+	}
+
+	return strings.Join(drifts, "; ")
+}
+
+// defaultAudioDrift describes a default output device that differs from the
+// profile. Nothing is reported when the profile device is not active.
+func defaultAudioDrift(profile Profile, devices []AudioDevice) string {
+	expected := profile.Audio.DefaultOutputDeviceId
+	if expected == "" {
+		return ""
+	}
+
+	available := false
+	currentDefault := ""
+	for _, device := range devices {
+		if device.ID == expected && device.State == audio.StateActive {
+			available = true
+		}
+		if device.IsDefault {
+			currentDefault = device.ID
+		}
+	}
+
+	if !available || currentDefault == expected {
+		return ""
+	}
+
+	return fmt.Sprintf("default output %s, expected %s", currentDefault, expected)
+}
+
+// correctAudioDrift restores the audio device states of the profile and then
+// its default output device, which enabling a device can make available again
+func (a *App) correctAudioDrift(profile Profile) error {
+	var errs []error
+	if len(profile.Audio.DeviceStates) > 0 {
+		if err := a.applyAudioDeviceStates(profile.Audio.DeviceStates); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	devices, err := a.readAudioDevices()
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	if defaultAudioDrift(profile, devices) != "" {
+		if err := a.audioTools.SetPrimaryDevice(profile.Audio.DefaultOutputDeviceId); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// recordDriftCorrection logs a correction and notifies subscribers
+func (a *App) recordDriftCorrection(profileName string, domain string, details string, err error) {
+	correction := DriftCorrection{
+		Time:    time.Now(),
+		Profile: profileName,
+		Domain:  domain,
+		Details: details,
+	}
+	if err != nil {
+		correction.Error = err.Error()
+	}
+
+	a.enforcer.record(correction)
+	slog.Info("Drift correction", "profile", profileName, "domain", domain, "details", details)
+
+	a.emitEvent(EventDriftCorrected, correction)
+}
+
+// GetEnforceSettings returns the drift enforcement settings
+func (a *App) GetEnforceSettings() EnforceSettings {
+	return a.currentSettings().Enforce
+}
+
+// SetEnforceSettings updates the drift enforcement settings
+func (a *App) SetEnforceSettings(settings EnforceSettings) error {
+	if settings.GracePeriodSeconds < 0 || settings.MinIntervalSeconds < 0 {
+		return fmt.Errorf("enforcement intervals cannot be negative")
+	}
+
+	err := a.updateSettings(func(s *Settings) error {
+		s.Enforce = settings
+		return nil
+	})
+	if !settings.Enabled {
+		a.enforcer.check.stop()
+	}
+
+	return err
+}
+
+// GetDriftCorrections returns the most recent drift corrections, oldest first
+func (a *App) GetDriftCorrections() []DriftCorrection {
+	a.enforcer.mu.Lock()
+	defer a.enforcer.mu.Unlock()
+
+	corrections := make([]DriftCorrection, len(a.enforcer.corrections))
+	copy(corrections, a.enforcer.corrections)
+	return corrections
+}
+
+// GetActiveProfile returns the name of the last applied profile
+func (a *App) GetActiveProfile() string {
+	return a.currentSettings().LastAppliedProfile
+}
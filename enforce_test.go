@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"monitor-profile-manager-wails/pkg/audio"
+	"monitor-profile-manager-wails/pkg/monitors"
+)
+
+// enableEnforcement turns drift enforcement on for the given active profile
+func enableEnforcement(t *testing.T, app *App, profileName string) {
+	t.Helper()
+
+	err := app.updateSettings(func(s *Settings) error {
+		s.Enforce = EnforceSettings{Enabled: true}
+		s.LastAppliedProfile = profileName
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMonitorDriftComparesSavedLayout(t *testing.T) {
+	app, tools := newTestApp(t)
+	desk := []fakeMonitor{
+		{name: `\\.\DISPLAY1`, id: "GSM5B7F", active: true, primary: true, width: 2560, height: 1440, frequency: 144},
+		{name: `\\.\DISPLAY2`, id: "DEL4321", active: true, width: 1920, height: 1080, x: 2560, frequency: 60},
+	}
+	tools.setMonitors(desk...)
+	if err := app.saveCurrentProfile("Desk"); err != nil {
+		t.Fatal(err)
+	}
+	profile := *app.findProfile("Desk")
+
+	if details := app.detectMonitorDrift(profile); details != "" {
+		t.Errorf("detectMonitorDrift() = %q, want no drift for the saved layout", details)
+	}
+
+	// The second monitor moved to the left of the first
+	moved := append([]fakeMonitor(nil), desk...)
+	moved[1].x = -1920
+	tools.setMonitors(moved...)
+
+	details := app.detectMonitorDrift(profile)
+	if !strings.Contains(details, `\\.\DISPLAY2 layout 1920x1080 at -1920,0 60Hz, expected 1920x1080 at 2560,0 60Hz`) {
+		t.Errorf("detectMonitorDrift() = %q, want the moved monitor", details)
+	}
+
+	enableEnforcement(t, app, "Desk")
+	tools.resetCalls()
+	app.enforceActiveProfile()
+
+	if !tools.called(monitors.MultiMonitorToolExe, "/LoadConfig "+app.getMonitorConfigPath("Desk")) {
+		t.Errorf("MultiMonitorTool calls = %q, want the saved configuration loaded", tools.calls(monitors.MultiMonitorToolExe))
+	}
+	corrections := app.GetDriftCorrections()
+	if len(corrections) != 1 || corrections[0].Domain != DriftDomainMonitors {
+		t.Errorf("corrections = %+v, want a single monitor correction", corrections)
+	}
+}
+
+func TestAudioDriftRestoresDeviceStates(t *testing.T) {
+	app, tools := newTestApp(t)
+	tools.setAudio(
+		fakeAudioDevice{id: "Speakers", name: "Speakers", state: audio.StateActive, isDefault: true},
+		fakeAudioDevice{id: "Headphones", name: "Headphones", state: audio.StateActive},
+		fakeAudioDevice{id: "TV", name: "TV", state: audio.StateUnplugged},
+	)
+	request := SaveProfileRequest{
+		Name:                  "Desk",
+		DefaultOutputDeviceId: "Speakers",
+		DeviceStates:          map[string]bool{"Speakers": true, "Headphones": true, "TV": true},
+	}
+	if err := app.SaveProfile(request); err != nil {
+		t.Fatal(err)
+	}
+	profile := *app.findProfile("Desk")
+
+	if details := app.detectAudioDrift(profile); details != "" {
+		t.Errorf("detectAudioDrift() = %q, want no drift", details)
+	}
+
+	// Headphones were disabled and became the default, the TV still unplugged
+	tools.setAudio(
+		fakeAudioDevice{id: "Speakers", name: "Speakers", state: audio.StateActive},
+		fakeAudioDevice{id: "Headphones", name: "Headphones", state: audio.StateDisabled, isDefault: true},
+		fakeAudioDevice{id: "TV", name: "TV", state: audio.StateUnplugged},
+	)
+
+	want := "Headphones enabled false, expected true; default output Headphones, expected Speakers"
+	if details := app.detectAudioDrift(profile); details != want {
+		t.Errorf("detectAudioDrift() = %q, want %q", details, want)
+	}
+
+	enableEnforcement(t, app, "Desk")
+	tools.resetCalls()
+	app.enforceActiveProfile()
+
+	if !tools.called(audio.SvclExe, "/Enable Headphones") || !tools.called(audio.SvclExe, "/SetDefault Speakers all") || tools.called(audio.SvclExe, "/Enable TV") {
+		t.Errorf("svcl calls = %q, want Headphones enabled and Speakers set as default", tools.calls(audio.SvclExe))
+	}
+	corrections := app.GetDriftCorrections()
+	if len(corrections) != 1 || corrections[0].Domain != DriftDomainAudio || corrections[0].Details != want {
+		t.Errorf("corrections = %+v, want a single audio correction", corrections)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	EVENT_STREAM_BUFFER        = 64 // events queued per client before it is dropped as too slow
+	EVENT_STREAM_PING_INTERVAL = 30 * time.Second
+	EVENT_STREAM_WRITE_TIMEOUT = 10 * time.Second
+	EVENT_STREAM_MAX_MESSAGE   = 64 * 1024
+)
+
+// eventStreamUpgrader only accepts browser connections from pages served by
+// this machine, so a web page open in the user's browser cannot send commands
+var eventStreamUpgrader = websocket.Upgrader{
+	CheckOrigin: eventStreamOriginAllowed,
+}
+
+// eventStreamOriginAllowed accepts clients that send no Origin, such as
+// scripts and Stream Deck plugins, and browser pages on localhost
+func eventStreamOriginAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	default:
+		return false
+	}
+}
+
+// streamCommand is a command sent by a WebSocket client. Command and Args
+// match the headless subcommands, e.g. {"command": "apply", "args": ["Desk"]}.
+type streamCommand struct {
+	ID      string   `json:"id"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+}
+
+// streamResponse answers a command. It has the type "response" so clients can
+// tell it apart from events.
+type streamResponse struct {
+	Type  string      `json:"type"`
+	ID    string      `json:"id,omitempty"`
+	OK    bool        `json:"ok"`
+	Error string      `json:"error,omitempty"`
+	Data  interface{} `json:"data,omitempty"`
+}
+
+// trackStream remembers an open connection so stop can close it
+func (s *apiServer) trackStream(conn *websocket.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.streams == nil {
+		s.streams = make(map[*websocket.Conn]struct{})
+	}
+	s.streams[conn] = struct{}{}
+}
+
+// untrackStream forgets a closed connection
+func (s *apiServer) untrackStream(conn *websocket.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.streams, conn)
+}
+
+// closeStreams closes every open connection, the server does not track
+// hijacked connections itself
+func (s *apiServer) closeStreams() {
+	s.mu.Lock()
+	streams := s.streams
+	s.streams = nil
+	s.mu.Unlock()
+
+	for conn := range streams {
+		conn.Close()
+	}
+}
+
+// serveEventStream upgrades the request to a WebSocket that receives every
+// application event as JSON and accepts commands in return
+func (a *App) serveEventStream(w http.ResponseWriter, r *http.Request) {
+	if !a.apiAuthorized(r) {
+		writeAPIResponse(w, http.StatusUnauthorized, apiError{Error: "invalid or missing API token"})
+		return
+	}
+
+	conn, err := eventStreamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader already replied with an error status
+		return
+	}
+	conn.SetReadLimit(EVENT_STREAM_MAX_MESSAGE)
+
+	a.api.trackStream(conn)
+	defer a.api.untrackStream(conn)
+	defer conn.Close()
+
+	outgoing := make(chan interface{}, EVENT_STREAM_BUFFER)
+	done := make(chan struct{})
+	defer close(done)
+
+	unsubscribe := a.events.Subscribe(func(event Event) {
+		select {
+		case outgoing <- event:
+		case <-done:
+		default:
+			// The client does not keep up, drop it instead of blocking the bus
+			conn.Close()
+		}
+	})
+	defer unsubscribe()
+
+	go a.writeEventStream(conn, outgoing, done)
+
+	// Pongs only extend the deadline, a client that stops answering pings is
+	// closed by the read failing
+	conn.SetReadDeadline(time.Now().Add(2 * EVENT_STREAM_PING_INTERVAL))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * EVENT_STREAM_PING_INTERVAL))
+	})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var command streamCommand
+		if err := json.Unmarshal(message, &command); err != nil {
+			a.queueStreamMessage(outgoing, done, streamResponse{Type: "response", Error: fmt.Sprintf("invalid command: %v", err)})
+			continue
+		}
+
+		// Commands such as applying a profile take a while, keep reading so
+		// the client can send more commands and pings in the meantime
+		go func() {
+			a.queueStreamMessage(outgoing, done, a.runStreamCommand(command))
+		}()
+	}
+}
+
+// queueStreamMessage hands a message to the writer unless the connection is closed
+func (a *App) queueStreamMessage(outgoing chan<- interface{}, done <-chan struct{}, message interface{}) {
+	select {
+	case outgoing <- message:
+	case <-done:
+	}
+}
+
+// writeEventStream writes queued messages and keeps the connection alive
+// with pings until the connection is closed
+func (a *App) writeEventStream(conn *websocket.Conn, outgoing <-chan interface{}, done <-chan struct{}) {
+	ticker := time.NewTicker(EVENT_STREAM_PING_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case message := <-outgoing:
+			conn.SetWriteDeadline(time.Now().Add(EVENT_STREAM_WRITE_TIMEOUT))
+			if err := conn.WriteJSON(message); err != nil {
+				conn.Close()
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(EVENT_STREAM_WRITE_TIMEOUT)); err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// runStreamCommand runs a command with the same implementation as the
+// headless subcommand of the same name
+func (a *App) runStreamCommand(command streamCommand) streamResponse {
+	response := streamResponse{Type: "response", ID: command.ID}
+
+	cliCommand := findCLICommand(command.Command)
+	if cliCommand == nil {
+		response.Error = fmt.Sprintf("unknown command: %s", command.Command)
+		return response
+	}
+	if len(command.Args) != len(cliCommand.args) {
+		response.Error = fmt.Sprintf("usage: %s", cliCommand.usage())
+		return response
+	}
+
+	data, err := cliCommand.run(a, command.Args)
+	if err != nil {
+		response.Error = err.Error()
+		return response
+	}
+
+	response.OK = true
+	response.Data = data
+	return response
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestEventStreamRejectsForeignOrigins(t *testing.T) {
+	app := newTestAPIApp(t)
+	server := httptest.NewServer(app.apiHandler())
+	defer server.Close()
+	streamURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/events"
+
+	for origin, allowed := range map[string]bool{
+		"":                        true,
+		"http://localhost:3000":   true,
+		"http://127.0.0.1:17830":  true,
+		"http://[::1]:8080":       true,
+		"https://example.com":     false,
+		"http://localhost.evil":   false,
+		"null":                    false,
+		"file://":                 false,
+		"chrome-extension://abcd": false,
+	} {
+		header := http.Header{"Authorization": {"Bearer " + apiTestToken}}
+		if origin != "" {
+			header.Set("Origin", origin)
+		}
+
+		conn, response, err := websocket.DefaultDialer.Dial(streamURL, header)
+		if conn != nil {
+			conn.Close()
+		}
+		if allowed && err != nil {
+			t.Errorf("origin %q was rejected: %v", origin, err)
+		}
+		if !allowed && (err == nil || response == nil || response.StatusCode != http.StatusForbidden) {
+			t.Errorf("origin %q was not rejected with 403: %v", origin, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Event types emitted to the frontend and to internal subscribers
+const (
+	EventMonitorAdded       = "monitor:added"
+	EventMonitorRemoved     = "monitor:removed"
+	EventMonitorChanged     = "monitor:changed"
+	EventAudioAdded         = "audio:added"
+	EventAudioRemoved       = "audio:removed"
+	EventAudioChanged       = "audio:changed"
+	EventAudioDefaultChange = "audio:default-changed"
+	EventDriftCorrected     = "profile:drift-corrected"
+	EventProfileAutoApplied = "profile:auto-applied"
+	EventProfileReverted    = "profile:reverted"
+	EventProfileApplied     = "profile:applied"
+	EventProfileApplyFailed = "profile:apply-failed"
+	EventProfileCreated     = "profile:created"
+	EventProfileUpdated     = "profile:updated"
+	EventProfileDeleted     = "profile:deleted"
+	EventHookFailed         = "profile:hook-failed"
+	EventToolIntegrity      = "tools:integrity"
+)
+
+// Event is a single application event. Data holds the event specific payload.
+type Event struct {
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// DeviceChange is the payload of monitor and audio device events. Previous is
+// set for removed and changed events, Current for added and changed events.
+type DeviceChange struct {
+	Monitor             *Monitor     `json:"monitor,omitempty"`
+	PreviousMonitor     *Monitor     `json:"previousMonitor,omitempty"`
+	AudioDevice         *AudioDevice `json:"audioDevice,omitempty"`
+	PreviousAudioDevice *AudioDevice `json:"previousAudioDevice,omitempty"`
+}
+
+// EventBus fans events out to internal subscribers
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[int]func(Event)
+	nextID      int
+}
+
+// NewEventBus creates an empty event bus
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[int]func(Event))}
+}
+
+// Subscribe registers a handler for every published event. The returned
+// function removes the handler again.
+func (b *EventBus) Subscribe(handler func(Event)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = handler
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, id)
+	}
+}
+
+// Publish delivers the event to every subscriber synchronously
+func (b *EventBus) Publish(event Event) {
+	b.mu.RLock()
+	handlers := make([]func(Event), 0, len(b.subscribers))
+	for _, handler := range b.subscribers {
+		handlers = append(handlers, handler)
+	}
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// emitEvent publishes an event to internal subscribers and to the frontend
+func (a *App) emitEvent(eventType string, data interface{}) {
+	event := Event{Type: eventType, Time: time.Now(), Data: data}
+
+	a.events.Publish(event)
+
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, eventType, event)
+	}
+}
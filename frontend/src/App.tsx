@@ -1,17 +1,20 @@
 import { useState, useEffect } from 'react';
 import { Layout, Typography, message as antMessage, Space, Alert, Row, Col, Card, Button } from 'antd';
-import { DesktopOutlined } from '@ant-design/icons';
+import { DesktopOutlined, FileZipOutlined } from '@ant-design/icons';
 import './App.css';
 import { MonitorsTable } from './components/monitors/MonitorsTable';
 import { AudioDevicesTable } from './components/audio/AudioDevicesTable';
 import { ProfileManagement } from './components/profiles/ProfileManagement';
+import { IgnoreRulesCard } from './components/ignore/IgnoreRulesCard';
 import { 
   GetMonitors, GetProfiles, RefreshMonitors,
-  GetAudioDevicesWithIgnoreStatus, RefreshAudioDevices
+  GetAudioDevicesWithIgnoreStatus, RefreshAudioDevices, ExportDiagnostics,
+  GetToolStatus, GetToolInfo
 } from "../wailsjs/go/main/App";
+import { EventsOn } from "../wailsjs/runtime/runtime";
 
 const { Header, Content } = Layout;
-const { Title } = Typography;
+const { Title, Text } = Typography;
 
 interface Monitor {
   deviceName: string;
@@ -34,6 +37,21 @@ interface AudioDevice {
   nickname: string;   // optional custom nickname
 }
 
+interface ToolStatus {
+  tool: string;
+  path: string;
+  state: string; // "verified", "restored" or "refused"
+  error?: string;
+}
+
+interface ToolInfo {
+  tool: string;
+  path: string;
+  source: string; // "override", "embedded", "development" or "path"
+  version?: string;
+  error?: string;
+}
+
 interface Profile {
   name: string;
   monitors?: Monitor[];
@@ -47,6 +65,8 @@ function App() {
   const [profiles, setProfiles] = useState<Profile[]>([]);
   const [loading, setLoading] = useState<boolean>(false);
   const [error, setError] = useState<string | null>(null);
+  const [toolStatus, setToolStatus] = useState<ToolStatus[]>([]);
+  const [toolInfo, setToolInfo] = useState<ToolInfo[]>([]);
 
   // Error boundary catch
   if (error) {
@@ -68,8 +88,46 @@ function App() {
 
   useEffect(() => {
     loadData();
+    reloadToolStatus();
+
+    // Reload device lists when the backend device watcher reports a change
+    const monitorEvents = ['monitor:added', 'monitor:removed', 'monitor:changed'];
+    const audioEvents = ['audio:added', 'audio:removed', 'audio:changed', 'audio:default-changed'];
+    const unsubscribers = [
+      ...monitorEvents.map(eventName => EventsOn(eventName, reloadMonitors)),
+      ...audioEvents.map(eventName => EventsOn(eventName, reloadAudioDevices)),
+      EventsOn('tools:integrity', reloadToolStatus),
+    ];
+
+    return () => unsubscribers.forEach(unsubscribe => unsubscribe());
   }, []);
 
+  const reloadToolStatus = async () => {
+    try {
+      setToolStatus(await GetToolStatus());
+      setToolInfo(await GetToolInfo());
+    } catch (error) {
+      console.error('Error loading tool status:', error);
+    }
+  };
+
+  const reloadMonitors = async () => {
+    try {
+      setMonitors(await GetMonitors());
+    } catch (error) {
+      console.error('Error reloading monitors:', error);
+    }
+  };
+
+  const reloadAudioDevices = async () => {
+    try {
+      const audioData = await GetAudioDevicesWithIgnoreStatus();
+      setAudioDevices(audioData as {filtered: AudioDevice[], ignored: AudioDevice[]});
+    } catch (error) {
+      console.error('Error reloading audio devices:', error);
+    }
+  };
+
   const loadData = async () => {
     try {
       setLoading(true);
@@ -164,6 +222,22 @@ function App() {
     }
   };
 
+  const handleExportDiagnostics = async () => {
+    try {
+      const archivePath = await ExportDiagnostics('', true);
+      if (archivePath) {
+        antMessage.success(`Diagnostics saved to ${archivePath}`);
+      }
+    } catch (error) {
+      antMessage.error(`Error exporting diagnostics: ${error}`);
+    }
+  };
+
+  const handleIgnoreRulesChange = () => {
+    reloadMonitors();
+    reloadAudioDevices();
+  };
+
   const handleProfilesChange = async () => {
     try {
       const profilesData = await GetProfiles();
@@ -179,14 +253,37 @@ function App() {
         background: 'rgba(255, 255, 255, 0.95)', 
         padding: '0 24px',
         boxShadow: '0 2px 8px rgba(0, 0, 0, 0.1)',
-        backdropFilter: 'blur(10px)'
+        backdropFilter: 'blur(10px)',
+        display: 'flex',
+        alignItems: 'center',
+        justifyContent: 'space-between'
       }}>
         <Title level={2} style={{ margin: '16px 0', color: '#1a1a1a' }}>
           <DesktopOutlined /> Windows Profile Manager
         </Title>
+        <Space size="large">
+          <Text type="secondary">
+            {toolInfo.map(info => `${info.tool} ${info.version || 'unknown version'} (${info.source || 'missing'})`).join(' · ')}
+          </Text>
+          <Button icon={<FileZipOutlined />} onClick={handleExportDiagnostics}>
+            Export Diagnostics
+          </Button>
+        </Space>
       </Header>
 
       <Content style={{ padding: '24px', maxWidth: '1400px', margin: '0 auto' }}>
+        {toolStatus.filter(status => status.state !== 'verified').map(status => (
+          <Alert
+            key={status.tool}
+            style={{ marginBottom: '24px' }}
+            type={status.state === 'refused' ? 'error' : 'warning'}
+            showIcon
+            message={status.state === 'refused'
+              ? `${status.tool} failed its integrity check and will not be run`
+              : `${status.tool} did not match its recorded checksum and was extracted again`}
+            description={status.error}
+          />
+        ))}
         <Row gutter={[24, 24]}>
           <Col xs={24} xl={16}>
             <Space direction="vertical" style={{ width: '100%' }} size="large">
@@ -203,6 +300,10 @@ function App() {
                 loading={loading}
                 onRefresh={handleRefreshAudio}
               />
+
+              <IgnoreRulesCard 
+                onRulesChange={handleIgnoreRulesChange}
+              />
             </Space>
           </Col>
 
@@ -7,7 +7,7 @@ import {
 } from '@ant-design/icons';
 import type { ColumnsType } from 'antd/es/table';
 import { 
-  SetAudioDeviceNickname, SetPrimaryOutputDevice, 
+  SetAudioDeviceNickname, SetPrimaryOutputDevice, SetAudioDeviceEnabled,
   IgnoreAudioDevice, UnignoreAudioDevice
 } from "../../../wailsjs/go/main/App";
 
@@ -68,6 +68,15 @@ export function AudioDevicesTable({
     }
   };
 
+  const handleSetAudioDeviceEnabled = async (deviceId: string, enabled: boolean) => {
+    try {
+      await SetAudioDeviceEnabled(deviceId, enabled);
+      window.location.reload();
+    } catch (error) {
+      console.error('Error setting audio device enabled:', error);
+    }
+  };
+
   const handleIgnoreAudioDevice = async (deviceId: string) => {
     try {
       await IgnoreAudioDevice(deviceId);
@@ -96,11 +105,29 @@ export function AudioDevicesTable({
           size="small"
           type={record.isDefault ? "primary" : "default"}
           onClick={() => handleSetDefaultAudioDevice(record.id)}
+          disabled={record.state !== 'Active'}
         >
           {record.isDefault ? 'Default' : 'Set Default'}
         </Button>
       )
     },
+    {
+      title: 'State',
+      key: 'state',
+      width: 150,
+      render: (_, record: AudioDevice) => (
+        <Space>
+          <Tag color={record.state === 'Active' ? 'success' : record.state === 'Disabled' ? 'error' : 'default'}>
+            {record.state}
+          </Tag>
+          <Switch
+            size="small"
+            checked={record.isEnabled}
+            onChange={(checked) => handleSetAudioDeviceEnabled(record.id, checked)}
+          />
+        </Space>
+      )
+    },
     {
       title: 'Name',
       key: 'name',
@@ -203,7 +230,7 @@ export function AudioDevicesTable({
             size="small"
           />
           <span style={{ fontSize: '12px', color: '#666' }}>
-            {showIgnoredAudio ? 'Ignored' : 'Active'}
+            {showIgnoredAudio ? 'Ignored' : 'Visible'}
           </span>
         </Space>
       }
@@ -0,0 +1,154 @@
+import { useState, useEffect } from 'react';
+import {
+  Table, Button, Input, Select, Space, Tag, Tooltip, Card, message as antMessage
+} from 'antd';
+import {
+  EyeInvisibleOutlined, DeleteOutlined, PlusOutlined
+} from '@ant-design/icons';
+import type { ColumnsType } from 'antd/es/table';
+import {
+  GetIgnoreRules, AddIgnoreRule, RemoveIgnoreRule
+} from "../../../wailsjs/go/main/App";
+import { main } from "../../../wailsjs/go/models";
+
+interface IgnoreRulesCardProps {
+  onRulesChange: () => void;
+}
+
+const matchLabels: Record<string, string> = {
+  id: 'Exact ID',
+  glob: 'Name glob',
+  regex: 'Name regex',
+};
+
+const patternPlaceholders: Record<string, string> = {
+  id: 'Monitor ID or audio device ID',
+  glob: 'e.g. NVIDIA Virtual Audio*',
+  regex: 'e.g. (?i)^steam streaming',
+};
+
+export function IgnoreRulesCard({ onRulesChange }: IgnoreRulesCardProps) {
+  const [rules, setRules] = useState<main.IgnoreRule[]>([]);
+  const [target, setTarget] = useState<string>('audio');
+  const [match, setMatch] = useState<string>('glob');
+  const [pattern, setPattern] = useState<string>('');
+
+  const loadRules = async () => {
+    try {
+      setRules(await GetIgnoreRules() || []);
+    } catch (error) {
+      console.error('Error loading ignore rules:', error);
+    }
+  };
+
+  useEffect(() => {
+    loadRules();
+  }, []);
+
+  const handleAddRule = async () => {
+    try {
+      await AddIgnoreRule(new main.IgnoreRule({ target, match, pattern: pattern.trim() }));
+      setPattern('');
+      await loadRules();
+      onRulesChange();
+    } catch (error) {
+      antMessage.error(`Error adding ignore rule: ${error}`);
+    }
+  };
+
+  const handleRemoveRule = async (rule: main.IgnoreRule) => {
+    try {
+      await RemoveIgnoreRule(rule);
+      await loadRules();
+      onRulesChange();
+    } catch (error) {
+      antMessage.error(`Error removing ignore rule: ${error}`);
+    }
+  };
+
+  const ruleColumns: ColumnsType<main.IgnoreRule> = [
+    {
+      title: 'Target',
+      dataIndex: 'target',
+      key: 'target',
+      width: 100,
+      render: (ruleTarget: string) => (
+        <Tag color={ruleTarget === 'monitor' ? 'blue' : 'green'}>
+          {ruleTarget === 'monitor' ? 'Monitor' : 'Audio'}
+        </Tag>
+      )
+    },
+    {
+      title: 'Match',
+      dataIndex: 'match',
+      key: 'match',
+      width: 120,
+      render: (ruleMatch: string) => matchLabels[ruleMatch] || ruleMatch
+    },
+    {
+      title: 'Pattern',
+      dataIndex: 'pattern',
+      key: 'pattern',
+      render: (rulePattern: string) => <code>{rulePattern}</code>
+    },
+    {
+      title: 'Actions',
+      key: 'actions',
+      width: 80,
+      render: (_, record: main.IgnoreRule) => (
+        <Tooltip title="Remove rule">
+          <Button
+            size="small"
+            danger
+            icon={<DeleteOutlined />}
+            onClick={() => handleRemoveRule(record)}
+          />
+        </Tooltip>
+      )
+    }
+  ];
+
+  return (
+    <Card
+      title={
+        <Space>
+          <EyeInvisibleOutlined />
+          <span>Ignore Rules</span>
+        </Space>
+      }
+    >
+      <Space.Compact style={{ width: '100%', marginBottom: 16 }}>
+        <Select value={target} onChange={setTarget} style={{ width: 120 }}>
+          <Select.Option value="audio">Audio</Select.Option>
+          <Select.Option value="monitor">Monitor</Select.Option>
+        </Select>
+        <Select value={match} onChange={setMatch} style={{ width: 130 }}>
+          {Object.entries(matchLabels).map(([value, label]) => (
+            <Select.Option key={value} value={value}>{label}</Select.Option>
+          ))}
+        </Select>
+        <Input
+          value={pattern}
+          onChange={(e) => setPattern(e.target.value)}
+          onPressEnter={handleAddRule}
+          placeholder={patternPlaceholders[match]}
+        />
+        <Button
+          type="primary"
+          icon={<PlusOutlined />}
+          onClick={handleAddRule}
+          disabled={!pattern.trim()}
+        >
+          Add
+        </Button>
+      </Space.Compact>
+      <Table
+        columns={ruleColumns}
+        dataSource={rules}
+        rowKey={(rule) => `${rule.target}|${rule.match}|${rule.pattern}`}
+        pagination={false}
+        size="small"
+      />
+    </Card>
+  );
+}
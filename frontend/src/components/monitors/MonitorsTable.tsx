@@ -7,7 +7,7 @@ import {
 } from '@ant-design/icons';
 import type { ColumnsType } from 'antd/es/table';
 import { 
-  SetMonitorNickname, SetMonitorPrimary, SetMonitorEnabledState
+  SetMonitorNickname, SetMonitorPrimary, SetMonitorEnabledState, IgnoreMonitor
 } from "../../../wailsjs/go/main/App";
 
 interface Monitor {
@@ -43,6 +43,12 @@ export function MonitorsTable({ monitors, loading, onRefresh }: MonitorsTablePro
       label: 'Set as Primary',
       onClick: () => handleSetMonitorPrimary(record.monitorId),
       disabled: record.isPrimary || !record.isEnabled || !record.isActive
+    },
+    {
+      key: 'ignore',
+      label: 'Ignore Monitor',
+      onClick: () => handleIgnoreMonitor(record.monitorId),
+      disabled: false
     }
   ];
 
@@ -83,6 +89,15 @@ export function MonitorsTable({ monitors, loading, onRefresh }: MonitorsTablePro
     }
   };
 
+  const handleIgnoreMonitor = async (monitorId: string) => {
+    try {
+      await IgnoreMonitor(monitorId);
+      window.location.reload();
+    } catch (error) {
+      console.error('Error ignoring monitor:', error);
+    }
+  };
+
   const monitorColumns: ColumnsType<Monitor> = [
     {
       title: 'State',
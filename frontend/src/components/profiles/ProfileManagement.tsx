@@ -1,18 +1,20 @@
-import { useState } from 'react';
+import { useState, useEffect } from 'react';
 import { 
-  Card, Typography, Button, Input, Select, Space, Divider, Tag
+  Card, Typography, Button, Input, Select, Space, Divider, Tag, Switch, Tooltip
 } from 'antd';
 import { 
   SaveOutlined, PlayCircleOutlined, EditOutlined,
-  DeleteOutlined
+  DeleteOutlined, UndoOutlined
 } from '@ant-design/icons';
 import { ConfirmProfileDelete } from './ConfirmProfileDelete';
 import { 
-  SaveProfile, ApplyProfile, GetProfiles, DeleteProfile
+  SaveProfile, ApplyProfile, GetProfiles, DeleteProfile,
+  GetEnforceSettings, SetEnforceSettings, GetActiveProfile, UndoLastApply,
+  SetProfileHotkey, GetLoginProfile, SetLoginProfile, GetTrayOrder, SetTrayOrder, GetApplyHistory
 } from "../../../wailsjs/go/main/App";
 import { main } from "../../../wailsjs/go/models";
 
-const { Title } = Typography;
+const { Title, Text } = Typography;
 
 interface Monitor {
   deviceName: string;
@@ -38,6 +40,7 @@ interface Profile {
   name: string;
   monitors?: Monitor[];
   audioDevices?: AudioDevice[];
+  hotkey?: string;
   audio?: {
     defaultOutputDeviceId: string;
   };
@@ -56,6 +59,54 @@ export function ProfileManagement({ profiles, loading, onProfilesChange, audioDe
   const [editingProfile, setEditingProfile] = useState<string | null>(null);
   const [deleteModalVisible, setDeleteModalVisible] = useState<boolean>(false);
   const [profileToDelete, setProfileToDelete] = useState<string>('');
+  const [enforceSettings, setEnforceSettings] = useState<main.EnforceSettings | null>(null);
+  const [activeProfile, setActiveProfile] = useState<string>('');
+  const [loginProfile, setLoginProfile] = useState<string>('');
+  const [trayOrder, setTrayOrder] = useState<string>('');
+  const [recentApplies, setRecentApplies] = useState<main.ApplyHistoryEntry[]>([]);
+
+  useEffect(() => {
+    GetEnforceSettings().then(setEnforceSettings).catch(error => console.error('Error loading enforce settings:', error));
+    GetActiveProfile().then(setActiveProfile).catch(error => console.error('Error loading active profile:', error));
+    GetLoginProfile().then(setLoginProfile).catch(error => console.error('Error loading login profile:', error));
+    GetTrayOrder().then(setTrayOrder).catch(error => console.error('Error loading tray order:', error));
+    GetApplyHistory(new main.ApplyHistoryFilter({ limit: 5 }))
+      .then(setRecentApplies)
+      .catch(error => console.error('Error loading apply history:', error));
+  }, [profiles, loading]);
+
+  const handleEnforceChange = async (enabled: boolean) => {
+    if (!enforceSettings) {
+      return;
+    }
+
+    try {
+      const updated = new main.EnforceSettings({ ...enforceSettings, enabled });
+      await SetEnforceSettings(updated);
+      setEnforceSettings(updated);
+    } catch (error) {
+      console.error('Error saving enforce settings:', error);
+    }
+  };
+
+  const handleLoginProfileChange = async (profileName?: string) => {
+    try {
+      await SetLoginProfile(profileName || '');
+      setLoginProfile(profileName || '');
+    } catch (error) {
+      console.error('Error saving login profile:', error);
+    }
+  };
+
+  const handleTrayOrderChange = async (mostUsed: boolean) => {
+    const order = mostUsed ? 'most-used' : '';
+    try {
+      await SetTrayOrder(order);
+      setTrayOrder(order);
+    } catch (error) {
+      console.error('Error saving tray order:', error);
+    }
+  };
 
   const handleSaveProfile = async () => {
     if (!profileName.trim()) {
@@ -66,10 +117,15 @@ export function ProfileManagement({ profiles, loading, onProfilesChange, audioDe
       // Automatically use the current default output audio device from system state
       const currentDefaultDevice = audioDevices.filtered.find(d => d.isDefault && (d.deviceType === 'output' || !d.deviceType));
       const defaultOutputDeviceId = currentDefaultDevice?.id || '';
+
+      // Capture the enabled state of every non-ignored device so applying restores it
+      const deviceStates: Record<string, boolean> = {};
+      audioDevices.filtered.forEach(d => { deviceStates[d.id] = d.isEnabled; });
       
       const saveRequest = new main.SaveProfileRequest({
         name: profileName,
-        defaultOutputDeviceId: defaultOutputDeviceId
+        defaultOutputDeviceId: defaultOutputDeviceId,
+        deviceStates: deviceStates
       });
       
       await SaveProfile(saveRequest);
@@ -113,6 +169,24 @@ export function ProfileManagement({ profiles, loading, onProfilesChange, audioDe
     }
   };
 
+  const handleUndoLastApply = async () => {
+    try {
+      await UndoLastApply();
+      window.location.reload();
+    } catch (error) {
+      console.error('Error reverting to previous state:', error);
+    }
+  };
+
+  const handleSetHotkey = async (profileName: string, hotkey: string) => {
+    try {
+      await SetProfileHotkey(profileName, hotkey);
+      onProfilesChange();
+    } catch (error) {
+      console.error('Error setting hotkey:', error);
+    }
+  };
+
   const showDeleteConfirm = (profileName: string) => {
     setProfileToDelete(profileName);
     setDeleteModalVisible(true);
@@ -207,9 +281,74 @@ export function ProfileManagement({ profiles, loading, onProfilesChange, audioDe
             >
               Apply Selected Profile
             </Button>
+            <Button 
+              icon={<UndoOutlined />}
+              onClick={handleUndoLastApply}
+              loading={loading}
+              style={{ width: '100%' }}
+            >
+              Revert to Previous
+            </Button>
+            <Space>
+              <Tooltip title="Re-apply the drifted parts of the last applied profile when Windows changes the monitor layout or default audio device">
+                <Switch
+                  size="small"
+                  checked={!!enforceSettings?.enabled}
+                  onChange={handleEnforceChange}
+                  disabled={!enforceSettings}
+                />
+              </Tooltip>
+              <span>Enforce active profile</span>
+              {activeProfile && <Tag color="blue">{activeProfile}</Tag>}
+            </Space>
+            <Select
+              style={{ width: '100%' }}
+              placeholder="Profile applied at login (--login)"
+              value={loginProfile || undefined}
+              onChange={handleLoginProfileChange}
+              allowClear
+            >
+              {profiles.map((profile) => (
+                <Select.Option key={profile.name} value={profile.name}>
+                  {profile.name}
+                </Select.Option>
+              ))}
+            </Select>
+            <Space>
+              <Switch
+                size="small"
+                checked={trayOrder === 'most-used'}
+                onChange={handleTrayOrderChange}
+              />
+              <span>Order tray menu by most used</span>
+            </Space>
           </Space>
         </div>
 
+        {recentApplies.length > 0 && (
+          <>
+            <Divider />
+            <div>
+              <Title level={5}>Recently Applied</Title>
+              <Space direction="vertical" style={{ width: '100%' }} size={4}>
+                {recentApplies.map((entry) => (
+                  <Tooltip
+                    key={`${entry.time}-${entry.profile}`}
+                    title={entry.error || entry.steps.map(step => `${step.name}: ${step.durationMs} ms`).join(', ')}
+                  >
+                    <Space>
+                      <Tag color={entry.outcome === 'success' ? 'green' : 'red'}>{entry.profile}</Tag>
+                      <Text type="secondary">
+                        {new Date(entry.time).toLocaleString()} via {entry.trigger} ({entry.durationMs} ms)
+                      </Text>
+                    </Space>
+                  </Tooltip>
+                ))}
+              </Space>
+            </div>
+          </>
+        )}
+
         {profiles.length > 0 && (
           <>
             <Divider />
@@ -259,6 +398,13 @@ export function ProfileManagement({ profiles, loading, onProfilesChange, audioDe
                         <Tag color="orange">{(profile.monitors || []).filter(m => m.isActive).length} active</Tag>
                         <Tag color="purple">{(profile.audioDevices || []).filter(d => d.isDefault).length} default</Tag>
                       </div>
+                      <Input
+                        size="small"
+                        placeholder="Hotkey, e.g. Ctrl+Alt+1"
+                        defaultValue={profile.hotkey}
+                        onPressEnter={(e) => handleSetHotkey(profile.name, e.currentTarget.value)}
+                        onBlur={(e) => e.target.value !== (profile.hotkey || '') && handleSetHotkey(profile.name, e.target.value)}
+                      />
                     </Space>
                   </Card>
                 ))}
@@ -1,33 +1,133 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {audit} from '../models';
+import {integrity} from '../models';
+import {logging} from '../models';
 import {main} from '../models';
+import {webhooks} from '../models';
+
+export function AddAudioRule(arg1:main.AudioRule):Promise<void>;
+
+export function AddIgnoreRule(arg1:main.IgnoreRule):Promise<void>;
+
+export function AddProcessRule(arg1:main.ProcessRule):Promise<void>;
+
+export function AddSchedule(arg1:main.ScheduleRule):Promise<void>;
+
+export function AddTopologyRule(arg1:main.TopologyRule):Promise<void>;
+
+export function AddWebhook(arg1:main.Webhook):Promise<main.Webhook>;
 
 export function ApplyProfile(arg1:string):Promise<void>;
 
 export function DeleteProfile(arg1:string):Promise<void>;
 
+export function ExportDiagnostics(arg1:string,arg2:boolean):Promise<string>;
+
+export function GetAPISettings():Promise<main.APISettings>;
+
+export function GetActiveProfile():Promise<string>;
+
+export function GetApplyHistory(arg1:main.ApplyHistoryFilter):Promise<Array<main.ApplyHistoryEntry>>;
+
 export function GetAudioDeviceNickname(arg1:string):Promise<string>;
 
 export function GetAudioDevices():Promise<Array<main.AudioDevice>>;
 
 export function GetAudioDevicesWithIgnoreStatus():Promise<Record<string, any>>;
 
+export function GetAudioRules():Promise<Array<main.AudioRule>>;
+
+export function GetCurrentTopology():Promise<Array<string>>;
+
+export function GetDriftCorrections():Promise<Array<main.DriftCorrection>>;
+
+export function GetEnforceSettings():Promise<main.EnforceSettings>;
+
+export function GetIgnoreRules():Promise<Array<main.IgnoreRule>>;
+
+export function GetLogLevel():Promise<string>;
+
+export function GetLoginProfile():Promise<string>;
+
+export function GetMQTTSettings():Promise<main.MQTTSettings>;
+
 export function GetMonitorNickname(arg1:string):Promise<string>;
 
 export function GetMonitors():Promise<Array<main.Monitor>>;
 
+export function GetMonitorsWithIgnoreStatus():Promise<Record<string, any>>;
+
+export function GetNextScheduledRuns(arg1:number):Promise<Array<main.ScheduledRun>>;
+
+export function GetProcessRules():Promise<Array<main.ProcessRule>>;
+
 export function GetProfiles():Promise<Array<main.Profile>>;
 
+export function GetRecentLogs(arg1:string,arg2:number):Promise<Array<logging.Entry>>;
+
+export function GetSchedules():Promise<Array<main.ScheduleRule>>;
+
+export function GetToolInfo():Promise<Array<main.ToolInfo>>;
+
+export function GetToolInvocations(arg1:main.ToolInvocationFilter):Promise<Array<audit.Invocation>>;
+
+export function GetToolPaths():Promise<main.ToolSettings>;
+
+export function GetToolStatus():Promise<Array<integrity.Status>>;
+
+export function GetTopologyRules():Promise<Array<main.TopologyRule>>;
+
+export function GetTrayOrder():Promise<string>;
+
+export function GetUndoStack():Promise<Array<main.SavedState>>;
+
+export function GetWebhookDeliveries():Promise<Array<webhooks.Delivery>>;
+
+export function GetWebhooks():Promise<Array<main.Webhook>>;
+
 export function IgnoreAudioDevice(arg1:string):Promise<void>;
 
+export function IgnoreMonitor(arg1:string):Promise<void>;
+
+export function IsAutomationPaused():Promise<boolean>;
+
 export function RefreshAudioDevices():Promise<Record<string, any>>;
 
 export function RefreshMonitors():Promise<Array<main.Monitor>>;
 
+export function RegenerateAPIToken():Promise<string>;
+
+export function RemoveAudioRule(arg1:string):Promise<void>;
+
+export function RemoveIgnoreRule(arg1:main.IgnoreRule):Promise<void>;
+
+export function RemoveProcessRule(arg1:string):Promise<void>;
+
+export function RemoveSchedule(arg1:string,arg2:string):Promise<void>;
+
+export function RemoveTopologyRule(arg1:Array<string>):Promise<void>;
+
+export function RemoveWebhook(arg1:string):Promise<void>;
+
 export function SaveProfile(arg1:main.SaveProfileRequest):Promise<void>;
 
+export function SetAPISettings(arg1:main.APISettings):Promise<void>;
+
+export function SetAudioDeviceEnabled(arg1:string,arg2:boolean):Promise<void>;
+
 export function SetAudioDeviceNickname(arg1:string,arg2:string):Promise<void>;
 
+export function SetAutomationPaused(arg1:boolean):Promise<void>;
+
+export function SetEnforceSettings(arg1:main.EnforceSettings):Promise<void>;
+
+export function SetLogLevel(arg1:string):Promise<void>;
+
+export function SetLoginProfile(arg1:string):Promise<void>;
+
+export function SetMQTTSettings(arg1:main.MQTTSettings):Promise<void>;
+
 export function SetMonitorEnabledState(arg1:string,arg2:boolean):Promise<void>;
 
 export function SetMonitorNickname(arg1:string,arg2:string):Promise<void>;
@@ -36,4 +136,16 @@ export function SetMonitorPrimary(arg1:string):Promise<void>;
 
 export function SetPrimaryOutputDevice(arg1:string):Promise<void>;
 
+export function SetProfileHooks(arg1:string,arg2:Array<main.ApplyHook>,arg3:Array<main.ApplyHook>):Promise<void>;
+
+export function SetProfileHotkey(arg1:string,arg2:string):Promise<void>;
+
+export function SetToolPath(arg1:string,arg2:string):Promise<void>;
+
+export function SetTrayOrder(arg1:string):Promise<void>;
+
+export function UndoLastApply():Promise<void>;
+
 export function UnignoreAudioDevice(arg1:string):Promise<void>;
+
+export function UnignoreMonitor(arg1:string):Promise<void>;
@@ -2,6 +2,30 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddAudioRule(arg1) {
+  return window['go']['main']['App']['AddAudioRule'](arg1);
+}
+
+export function AddIgnoreRule(arg1) {
+  return window['go']['main']['App']['AddIgnoreRule'](arg1);
+}
+
+export function AddProcessRule(arg1) {
+  return window['go']['main']['App']['AddProcessRule'](arg1);
+}
+
+export function AddSchedule(arg1) {
+  return window['go']['main']['App']['AddSchedule'](arg1);
+}
+
+export function AddTopologyRule(arg1) {
+  return window['go']['main']['App']['AddTopologyRule'](arg1);
+}
+
+export function AddWebhook(arg1) {
+  return window['go']['main']['App']['AddWebhook'](arg1);
+}
+
 export function ApplyProfile(arg1) {
   return window['go']['main']['App']['ApplyProfile'](arg1);
 }
@@ -10,6 +34,22 @@ export function DeleteProfile(arg1) {
   return window['go']['main']['App']['DeleteProfile'](arg1);
 }
 
+export function ExportDiagnostics(arg1, arg2) {
+  return window['go']['main']['App']['ExportDiagnostics'](arg1, arg2);
+}
+
+export function GetAPISettings() {
+  return window['go']['main']['App']['GetAPISettings']();
+}
+
+export function GetActiveProfile() {
+  return window['go']['main']['App']['GetActiveProfile']();
+}
+
+export function GetApplyHistory(arg1) {
+  return window['go']['main']['App']['GetApplyHistory'](arg1);
+}
+
 export function GetAudioDeviceNickname(arg1) {
   return window['go']['main']['App']['GetAudioDeviceNickname'](arg1);
 }
@@ -22,6 +62,38 @@ export function GetAudioDevicesWithIgnoreStatus() {
   return window['go']['main']['App']['GetAudioDevicesWithIgnoreStatus']();
 }
 
+export function GetAudioRules() {
+  return window['go']['main']['App']['GetAudioRules']();
+}
+
+export function GetCurrentTopology() {
+  return window['go']['main']['App']['GetCurrentTopology']();
+}
+
+export function GetDriftCorrections() {
+  return window['go']['main']['App']['GetDriftCorrections']();
+}
+
+export function GetEnforceSettings() {
+  return window['go']['main']['App']['GetEnforceSettings']();
+}
+
+export function GetIgnoreRules() {
+  return window['go']['main']['App']['GetIgnoreRules']();
+}
+
+export function GetLogLevel() {
+  return window['go']['main']['App']['GetLogLevel']();
+}
+
+export function GetLoginProfile() {
+  return window['go']['main']['App']['GetLoginProfile']();
+}
+
+export function GetMQTTSettings() {
+  return window['go']['main']['App']['GetMQTTSettings']();
+}
+
 export function GetMonitorNickname(arg1) {
   return window['go']['main']['App']['GetMonitorNickname'](arg1);
 }
@@ -30,14 +102,78 @@ export function GetMonitors() {
   return window['go']['main']['App']['GetMonitors']();
 }
 
+export function GetMonitorsWithIgnoreStatus() {
+  return window['go']['main']['App']['GetMonitorsWithIgnoreStatus']();
+}
+
+export function GetNextScheduledRuns(arg1) {
+  return window['go']['main']['App']['GetNextScheduledRuns'](arg1);
+}
+
+export function GetProcessRules() {
+  return window['go']['main']['App']['GetProcessRules']();
+}
+
 export function GetProfiles() {
   return window['go']['main']['App']['GetProfiles']();
 }
 
+export function GetRecentLogs(arg1, arg2) {
+  return window['go']['main']['App']['GetRecentLogs'](arg1, arg2);
+}
+
+export function GetSchedules() {
+  return window['go']['main']['App']['GetSchedules']();
+}
+
+export function GetToolInfo() {
+  return window['go']['main']['App']['GetToolInfo']();
+}
+
+export function GetToolInvocations(arg1) {
+  return window['go']['main']['App']['GetToolInvocations'](arg1);
+}
+
+export function GetToolPaths() {
+  return window['go']['main']['App']['GetToolPaths']();
+}
+
+export function GetToolStatus() {
+  return window['go']['main']['App']['GetToolStatus']();
+}
+
+export function GetTopologyRules() {
+  return window['go']['main']['App']['GetTopologyRules']();
+}
+
+export function GetTrayOrder() {
+  return window['go']['main']['App']['GetTrayOrder']();
+}
+
+export function GetUndoStack() {
+  return window['go']['main']['App']['GetUndoStack']();
+}
+
+export function GetWebhookDeliveries() {
+  return window['go']['main']['App']['GetWebhookDeliveries']();
+}
+
+export function GetWebhooks() {
+  return window['go']['main']['App']['GetWebhooks']();
+}
+
 export function IgnoreAudioDevice(arg1) {
   return window['go']['main']['App']['IgnoreAudioDevice'](arg1);
 }
 
+export function IgnoreMonitor(arg1) {
+  return window['go']['main']['App']['IgnoreMonitor'](arg1);
+}
+
+export function IsAutomationPaused() {
+  return window['go']['main']['App']['IsAutomationPaused']();
+}
+
 export function RefreshAudioDevices() {
   return window['go']['main']['App']['RefreshAudioDevices']();
 }
@@ -46,14 +182,70 @@ export function RefreshMonitors() {
   return window['go']['main']['App']['RefreshMonitors']();
 }
 
+export function RegenerateAPIToken() {
+  return window['go']['main']['App']['RegenerateAPIToken']();
+}
+
+export function RemoveAudioRule(arg1) {
+  return window['go']['main']['App']['RemoveAudioRule'](arg1);
+}
+
+export function RemoveIgnoreRule(arg1) {
+  return window['go']['main']['App']['RemoveIgnoreRule'](arg1);
+}
+
+export function RemoveProcessRule(arg1) {
+  return window['go']['main']['App']['RemoveProcessRule'](arg1);
+}
+
+export function RemoveSchedule(arg1, arg2) {
+  return window['go']['main']['App']['RemoveSchedule'](arg1, arg2);
+}
+
+export function RemoveTopologyRule(arg1) {
+  return window['go']['main']['App']['RemoveTopologyRule'](arg1);
+}
+
+export function RemoveWebhook(arg1) {
+  return window['go']['main']['App']['RemoveWebhook'](arg1);
+}
+
 export function SaveProfile(arg1) {
   return window['go']['main']['App']['SaveProfile'](arg1);
 }
 
+export function SetAPISettings(arg1) {
+  return window['go']['main']['App']['SetAPISettings'](arg1);
+}
+
+export function SetAudioDeviceEnabled(arg1, arg2) {
+  return window['go']['main']['App']['SetAudioDeviceEnabled'](arg1, arg2);
+}
+
 export function SetAudioDeviceNickname(arg1, arg2) {
   return window['go']['main']['App']['SetAudioDeviceNickname'](arg1, arg2);
 }
 
+export function SetAutomationPaused(arg1) {
+  return window['go']['main']['App']['SetAutomationPaused'](arg1);
+}
+
+export function SetEnforceSettings(arg1) {
+  return window['go']['main']['App']['SetEnforceSettings'](arg1);
+}
+
+export function SetLogLevel(arg1) {
+  return window['go']['main']['App']['SetLogLevel'](arg1);
+}
+
+export function SetLoginProfile(arg1) {
+  return window['go']['main']['App']['SetLoginProfile'](arg1);
+}
+
+export function SetMQTTSettings(arg1) {
+  return window['go']['main']['App']['SetMQTTSettings'](arg1);
+}
+
 export function SetMonitorEnabledState(arg1, arg2) {
   return window['go']['main']['App']['SetMonitorEnabledState'](arg1, arg2);
 }
@@ -70,6 +262,30 @@ export function SetPrimaryOutputDevice(arg1) {
   return window['go']['main']['App']['SetPrimaryOutputDevice'](arg1);
 }
 
+export function SetProfileHooks(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetProfileHooks'](arg1, arg2, arg3);
+}
+
+export function SetProfileHotkey(arg1, arg2) {
+  return window['go']['main']['App']['SetProfileHotkey'](arg1, arg2);
+}
+
+export function SetToolPath(arg1, arg2) {
+  return window['go']['main']['App']['SetToolPath'](arg1, arg2);
+}
+
+export function SetTrayOrder(arg1) {
+  return window['go']['main']['App']['SetTrayOrder'](arg1);
+}
+
+export function UndoLastApply() {
+  return window['go']['main']['App']['UndoLastApply']();
+}
+
 export function UnignoreAudioDevice(arg1) {
   return window['go']['main']['App']['UnignoreAudioDevice'](arg1);
 }
+
+export function UnignoreMonitor(arg1) {
+  return window['go']['main']['App']['UnignoreMonitor'](arg1);
+}
@@ -1,10 +1,287 @@
+export namespace audit {
+	
+	export class Invocation {
+	    // Go type: time
+	    time: any;
+	    origin?: string;
+	    tool: string;
+	    path: string;
+	    args: string[];
+	    durationMs: number;
+	    exitCode: number;
+	    stdout?: string;
+	    stderr?: string;
+	    truncated?: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Invocation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.origin = source["origin"];
+	        this.tool = source["tool"];
+	        this.path = source["path"];
+	        this.args = source["args"];
+	        this.durationMs = source["durationMs"];
+	        this.exitCode = source["exitCode"];
+	        this.stdout = source["stdout"];
+	        this.stderr = source["stderr"];
+	        this.truncated = source["truncated"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace integrity {
+	
+	export class Status {
+	    tool: string;
+	    path: string;
+	    expected: string;
+	    actual?: string;
+	    state: string;
+	    error?: string;
+	    // Go type: time
+	    checked: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Status(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool = source["tool"];
+	        this.path = source["path"];
+	        this.expected = source["expected"];
+	        this.actual = source["actual"];
+	        this.state = source["state"];
+	        this.error = source["error"];
+	        this.checked = this.convertValues(source["checked"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace logging {
+	
+	export class Entry {
+	    // Go type: time
+	    time: any;
+	    level: string;
+	    message: string;
+	    attrs?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.level = source["level"];
+	        this.message = source["message"];
+	        this.attrs = source["attrs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace main {
 	
+	export class APISettings {
+	    enabled: boolean;
+	    port: number;
+	    token: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new APISettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	        this.token = source["token"];
+	    }
+	}
+	export class ApplyHistoryEntry {
+	    // Go type: time
+	    time: any;
+	    profile: string;
+	    trigger: string;
+	    outcome: string;
+	    error?: string;
+	    durationMs: number;
+	    steps: ApplyStep[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ApplyHistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.profile = source["profile"];
+	        this.trigger = source["trigger"];
+	        this.outcome = source["outcome"];
+	        this.error = source["error"];
+	        this.durationMs = source["durationMs"];
+	        this.steps = this.convertValues(source["steps"], ApplyStep);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ApplyHistoryFilter {
+	    profile: string;
+	    trigger: string;
+	    outcome: string;
+	    // Go type: time
+	    since: any;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ApplyHistoryFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.profile = source["profile"];
+	        this.trigger = source["trigger"];
+	        this.outcome = source["outcome"];
+	        this.since = this.convertValues(source["since"], null);
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ApplyHook {
+	    command: string;
+	    timeoutSeconds: number;
+	    failurePolicy: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ApplyHook(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.command = source["command"];
+	        this.timeoutSeconds = source["timeoutSeconds"];
+	        this.failurePolicy = source["failurePolicy"];
+	    }
+	}
+	export class ApplyStep {
+	    name: string;
+	    durationMs: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ApplyStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.durationMs = source["durationMs"];
+	        this.error = source["error"];
+	    }
+	}
 	export class AudioDevice {
 	    id: string;
 	    name: string;
 	    isDefault: boolean;
 	    isEnabled: boolean;
+	    state: string;
 	    selected: boolean;
 	    nickname: string;
 	
@@ -18,12 +295,14 @@ export namespace main {
 	        this.name = source["name"];
 	        this.isDefault = source["isDefault"];
 	        this.isEnabled = source["isEnabled"];
+	        this.state = source["state"];
 	        this.selected = source["selected"];
 	        this.nickname = source["nickname"];
 	    }
 	}
 	export class AudioProfile {
 	    defaultOutputDeviceId: string;
+	    deviceStates?: Record<string, boolean>;
 	
 	    static createFrom(source: any = {}) {
 	        return new AudioProfile(source);
@@ -32,6 +311,118 @@ export namespace main {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.defaultOutputDeviceId = source["defaultOutputDeviceId"];
+	        this.deviceStates = source["deviceStates"];
+	    }
+	}
+	export class AudioRule {
+	    deviceId: string;
+	    connectProfile: string;
+	    disconnectProfile: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AudioRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deviceId = source["deviceId"];
+	        this.connectProfile = source["connectProfile"];
+	        this.disconnectProfile = source["disconnectProfile"];
+	    }
+	}
+	export class DriftCorrection {
+	    // Go type: time
+	    time: any;
+	    profile: string;
+	    domain: string;
+	    details: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DriftCorrection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.profile = source["profile"];
+	        this.domain = source["domain"];
+	        this.details = source["details"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EnforceSettings {
+	    enabled: boolean;
+	    gracePeriodSeconds: number;
+	    minIntervalSeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnforceSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.gracePeriodSeconds = source["gracePeriodSeconds"];
+	        this.minIntervalSeconds = source["minIntervalSeconds"];
+	    }
+	}
+	export class IgnoreRule {
+	    target: string;
+	    match: string;
+	    pattern: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new IgnoreRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = source["target"];
+	        this.match = source["match"];
+	        this.pattern = source["pattern"];
+	    }
+	}
+	export class MQTTSettings {
+	    enabled: boolean;
+	    broker: string;
+	    username: string;
+	    password: string;
+	    topicPrefix: string;
+	    discovery: boolean;
+	    discoveryPrefix: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MQTTSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.broker = source["broker"];
+	        this.username = source["username"];
+	        this.password = source["password"];
+	        this.topicPrefix = source["topicPrefix"];
+	        this.discovery = source["discovery"];
+	        this.discoveryPrefix = source["discoveryPrefix"];
 	    }
 	}
 	export class Monitor {
@@ -39,6 +430,7 @@ export namespace main {
 	    displayName: string;
 	    isPrimary: boolean;
 	    isActive: boolean;
+	    isConnected: boolean;
 	    isEnabled: boolean;
 	    monitorId: string;
 	    nickname: string;
@@ -53,14 +445,33 @@ export namespace main {
 	        this.displayName = source["displayName"];
 	        this.isPrimary = source["isPrimary"];
 	        this.isActive = source["isActive"];
+	        this.isConnected = source["isConnected"];
 	        this.isEnabled = source["isEnabled"];
 	        this.monitorId = source["monitorId"];
 	        this.nickname = source["nickname"];
 	    }
 	}
+	export class ProcessRule {
+	    executable: string;
+	    profile: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProcessRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.executable = source["executable"];
+	        this.profile = source["profile"];
+	    }
+	}
 	export class Profile {
 	    name: string;
 	    audio: AudioProfile;
+	    monitors?: Monitor[];
+	    hotkey?: string;
+	    preApply?: ApplyHook[];
+	    postApply?: ApplyHook[];
 	
 	    static createFrom(source: any = {}) {
 	        return new Profile(source);
@@ -70,6 +481,10 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
 	        this.audio = this.convertValues(source["audio"], AudioProfile);
+	        this.monitors = this.convertValues(source["monitors"], Monitor);
+	        this.hotkey = source["hotkey"];
+	        this.preApply = this.convertValues(source["preApply"], ApplyHook);
+	        this.postApply = this.convertValues(source["postApply"], ApplyHook);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -93,6 +508,7 @@ export namespace main {
 	export class SaveProfileRequest {
 	    name: string;
 	    defaultOutputDeviceId: string;
+	    deviceStates: Record<string, boolean>;
 	
 	    static createFrom(source: any = {}) {
 	        return new SaveProfileRequest(source);
@@ -102,8 +518,239 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
 	        this.defaultOutputDeviceId = source["defaultOutputDeviceId"];
+	        this.deviceStates = source["deviceStates"];
 	    }
 	}
+	export class SavedState {
+	    id: string;
+	    // Go type: time
+	    time: any;
+	    profile: string;
+	    monitorConfig: string;
+	    defaultOutputDeviceId: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SavedState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.profile = source["profile"];
+	        this.monitorConfig = source["monitorConfig"];
+	        this.defaultOutputDeviceId = source["defaultOutputDeviceId"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScheduleRule {
+	    cron: string;
+	    profile: string;
+	    missedRunPolicy: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScheduleRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cron = source["cron"];
+	        this.profile = source["profile"];
+	        this.missedRunPolicy = source["missedRunPolicy"];
+	    }
+	}
+	export class ScheduledRun {
+	    cron: string;
+	    profile: string;
+	    // Go type: time
+	    time: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScheduledRun(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cron = source["cron"];
+	        this.profile = source["profile"];
+	        this.time = this.convertValues(source["time"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ToolInfo {
+	    tool: string;
+	    path: string;
+	    source: string;
+	    version?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool = source["tool"];
+	        this.path = source["path"];
+	        this.source = source["source"];
+	        this.version = source["version"];
+	        this.error = source["error"];
+	    }
+	}
+	export class ToolInvocationFilter {
+	    tool: string;
+	    failedOnly: boolean;
+	    includePolls: boolean;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolInvocationFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool = source["tool"];
+	        this.failedOnly = source["failedOnly"];
+	        this.includePolls = source["includePolls"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class ToolSettings {
+	    svclPath: string;
+	    multiMonitorToolPath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.svclPath = source["svclPath"];
+	        this.multiMonitorToolPath = source["multiMonitorToolPath"];
+	    }
+	}
+	export class TopologyRule {
+	    monitors: string[];
+	    profile: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TopologyRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.monitors = source["monitors"];
+	        this.profile = source["profile"];
+	    }
+	}
+
+
+
+
+
+
+
+
+	export class Webhook {
+	    url: string;
+	    secret: string;
+	    events: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Webhook(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.secret = source["secret"];
+	        this.events = source["events"];
+	    }
+	}
+
+}
+
+export namespace webhooks {
+	
+	export class Delivery {
+	    id: string;
+	    url: string;
+	    event: string;
+	    // Go type: time
+	    time: any;
+	    attempts: number;
+	    statusCode?: number;
+	    success: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Delivery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.url = source["url"];
+	        this.event = source["event"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.attempts = source["attempts"];
+	        this.statusCode = source["statusCode"];
+	        this.success = source["success"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"monitor-profile-manager-wails/pkg/audio"
+	"monitor-profile-manager-wails/pkg/monitors"
+)
+
+// fakeMonitor is a row of the fake MultiMonitorTool /List output
+type fakeMonitor struct {
+	name         string // device name, e.g. \\.\DISPLAY1
+	id           string // short monitor ID
+	displayName  string
+	active       bool
+	primary      bool
+	disconnected bool
+	width        int // resolution and position, reported when width is set
+	height       int
+	x            int
+	y            int
+	frequency    int
+}
+
+// fakeAudioDevice is a row of the fake svcl /scomma output
+type fakeAudioDevice struct {
+	id        string
+	name      string
+	state     string
+	isDefault bool
+}
+
+// fakeTools stands in for MultiMonitorTool and svcl. Both are shell scripts
+// that log their arguments and print the devices set with setMonitors and
+// setAudio. Arguments listed in <tool>.fail make the tool exit with 1.
+type fakeTools struct {
+	t   *testing.T
+	dir string
+}
+
+const (
+	fakeMultiMonitorTool = `#!/bin/sh
+echo "$*" >> "%[1]s/MultiMonitorTool.exe.log"
+grep -qxF "$*" "%[1]s/MultiMonitorTool.exe.fail" 2>/dev/null && exit 1
+case "$1" in
+/List) cp "%[1]s/monitors.csv" "$3" ;;
+/SaveConfig) cp "%[1]s/monitors.cfg" "$2" ;;
+esac
+exit 0
+`
+	fakeSvcl = `#!/bin/sh
+echo "$*" >> "%[1]s/svcl.exe.log"
+grep -qxF "$*" "%[1]s/svcl.exe.fail" 2>/dev/null && exit 1
+case "$1" in
+/scomma) cat "%[1]s/audio.csv" ;;
+esac
+exit 0
+`
+)
+
+// newFakeTools writes the fake tools to a temporary directory
+func newFakeTools(t *testing.T) *fakeTools {
+	t.Helper()
+
+	tools := &fakeTools{t: t, dir: t.TempDir()}
+	tools.write(monitors.MultiMonitorToolExe, fmt.Sprintf(fakeMultiMonitorTool, tools.dir), 0755)
+	tools.write(audio.SvclExe, fmt.Sprintf(fakeSvcl, tools.dir), 0755)
+	tools.setMonitors()
+	tools.setAudio()
+	return tools
+}
+
+func (f *fakeTools) write(name string, content string, perm os.FileMode) {
+	f.t.Helper()
+	if err := os.WriteFile(filepath.Join(f.dir, name), []byte(content), perm); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+// path returns the path of a fake tool
+func (f *fakeTools) path(tool string) string {
+	return filepath.Join(f.dir, tool)
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
+
+// setMonitors sets the monitors reported by MultiMonitorTool /List and saved
+// by /SaveConfig
+func (f *fakeTools) setMonitors(list ...fakeMonitor) {
+	var csv, cfg strings.Builder
+	csv.WriteString("Name,Active,Disconnected,Primary,Resolution,Left-Top,Frequency,Short Monitor ID,Monitor Name,Monitor Serial Number\n")
+	for i, m := range list {
+		resolution, leftTop, frequency := "", "", ""
+		if m.width > 0 {
+			resolution = fmt.Sprintf("%d X %d", m.width, m.height)
+			leftTop = fmt.Sprintf(`"%d, %d"`, m.x, m.y)
+			frequency = fmt.Sprint(m.frequency)
+		}
+		fmt.Fprintf(&csv, "%s,%s,%s,%s,%s,%s,%s,%s,%s,SN%04d\n", m.name, yesNo(m.active), yesNo(m.disconnected), yesNo(m.primary), resolution, leftTop, frequency, m.id, m.displayName, 1000+i)
+		fmt.Fprintf(&cfg, "[Monitor%d]\nName=%s\nWidth=%d\nHeight=%d\nPositionX=%d\nPositionY=%d\nDisplayFrequency=%d\n", i, m.name, m.width, m.height, m.x, m.y, m.frequency)
+	}
+	f.write("monitors.csv", csv.String(), 0644)
+	f.write("monitors.cfg", cfg.String(), 0644)
+}
+
+// setAudio sets the output devices reported by svcl /scomma
+func (f *fakeTools) setAudio(devices ...fakeAudioDevice) {
+	var csv strings.Builder
+	csv.WriteString("Name,Type,Direction,Device Name,Default,Device State,Command-Line Friendly ID\n")
+	for _, d := range devices {
+		isDefault := ""
+		if d.isDefault {
+			isDefault = "Render"
+		}
+		fmt.Fprintf(&csv, "Speakers,Device,Render,%s,%s,%s,%s\n", d.name, isDefault, d.state, d.id)
+	}
+	f.write("audio.csv", csv.String(), 0644)
+}
+
+// failOn makes tool exit with 1 when called with exactly args
+func (f *fakeTools) failOn(tool string, args string) {
+	file, err := os.OpenFile(filepath.Join(f.dir, tool+".fail"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	defer file.Close()
+	fmt.Fprintln(file, args)
+}
+
+// calls returns the argument lists tool was run with
+func (f *fakeTools) calls(tool string) []string {
+	data, err := os.ReadFile(filepath.Join(f.dir, tool+".log"))
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+// called reports whether tool was run with exactly args
+func (f *fakeTools) called(tool string, args string) bool {
+	for _, call := range f.calls(tool) {
+		if call == args {
+			return true
+		}
+	}
+	return false
+}
+
+// resetCalls forgets the recorded calls
+func (f *fakeTools) resetCalls() {
+	os.Remove(filepath.Join(f.dir, monitors.MultiMonitorToolExe+".log"))
+	os.Remove(filepath.Join(f.dir, audio.SvclExe+".log"))
+}
+
+// newTestApp returns a headless App whose data directory is a temporary home
+// directory and whose tools are fakes
+func newTestApp(t *testing.T) (*App, *fakeTools) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake tools are shell scripts")
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	// MultiMonitorTool /List writes monitors.csv to the working directory
+	t.Chdir(t.TempDir())
+
+	tools := newFakeTools(t)
+
+	app := NewApp()
+	app.headless = true
+	app.audioTools = audio.NewAudioTools("", app.toolAudit, app.toolVerifier)
+	app.monitorTools = monitors.NewMonitorTools("", app.toolAudit, app.toolVerifier)
+	app.loadSettings()
+	for _, tool := range []string{audio.SvclExe, monitors.MultiMonitorToolExe} {
+		if err := app.SetToolPath(tool, tools.path(tool)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return app, tools
+}
+
+// waitFor polls condition until it holds or fails the test after timeout
+func waitFor(t *testing.T, timeout time.Duration, what string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
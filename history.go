@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	APPLY_HISTORY_FILE_NAME = "apply_history.jsonl"
+	MAX_APPLY_HISTORY       = 1000 // entries kept when the history file is trimmed
+	APPLY_HISTORY_SLACK     = 100  // entries appended past the maximum before trimming
+	TriggerUI               = "ui"
+	TriggerTray             = "tray"
+	TriggerCLI              = "cli"
+	TriggerWebSocket        = "websocket"
+)
+
+// Apply outcomes
+const (
+	ApplyOutcomeSuccess = "success"
+	ApplyOutcomeFailure = "failure"
+)
+
+// Tray profile orderings
+const (
+	TrayOrderSaved    = ""          // the order the profiles were saved in
+	TrayOrderMostUsed = "most-used" // most successful applies first
+)
+
+// applyHistory serializes access to the history file and caches what is
+// derived from it, so the file is only read once per run
+type applyHistory struct {
+	mu      sync.Mutex
+	loaded  bool
+	entries int            // entries in the history file
+	counts  map[string]int // successful applies per profile
+}
+
+// ApplyStep is a single step of a profile application
+type ApplyStep struct {
+	Name       string `json:"name"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ApplyHistoryEntry records one profile application
+type ApplyHistoryEntry struct {
+	Time       time.Time   `json:"time"`
+	Profile    string      `json:"profile"`
+	Trigger    string      `json:"trigger"` // ui, tray, hotkey, cli, websocket or an automation trigger such as schedule
+	Outcome    string      `json:"outcome"`
+	Error      string      `json:"error,omitempty"`
+	DurationMs int64       `json:"durationMs"`
+	Steps      []ApplyStep `json:"steps"`
+}
+
+// ApplyHistoryFilter selects apply history entries, empty fields match everything
+type ApplyHistoryFilter struct {
+	Profile string    `json:"profile"`
+	Trigger string    `json:"trigger"`
+	Outcome string    `json:"outcome"`
+	Since   time.Time `json:"since"`
+	Limit   int       `json:"limit"` // maximum number of entries, 0 returns all
+}
+
+// matches reports whether an entry passes the filter
+func (f ApplyHistoryFilter) matches(entry ApplyHistoryEntry) bool {
+	if f.Profile != "" && entry.Profile != f.Profile {
+		return false
+	}
+	if f.Trigger != "" && entry.Trigger != f.Trigger {
+		return false
+	}
+	if f.Outcome != "" && entry.Outcome != f.Outcome {
+		return false
+	}
+	return f.Since.IsZero() || !entry.Time.Before(f.Since)
+}
+
+// applySteps times the steps of a profile application
+type applySteps []ApplyStep
+
+// run runs and records a step, the error of the step is returned
+func (s *applySteps) run(name string, step func() error) error {
+	start := time.Now()
+	err := step()
+
+	record := ApplyStep{Name: name, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	*s = append(*s, record)
+
+	return err
+}
+
+// getApplyHistoryPath returns the path where the apply history is stored
+func (a *App) getApplyHistoryPath() string {
+	return filepath.Join(a.getProfilesDir(), APPLY_HISTORY_FILE_NAME)
+}
+
+// appendApplyHistory appends an entry to the history file, one JSON object
+// per line. Once the file holds APPLY_HISTORY_SLACK entries more than
+// MAX_APPLY_HISTORY it is trimmed to the newest MAX_APPLY_HISTORY.
+func (a *App) appendApplyHistory(entry ApplyHistoryEntry) error {
+	a.history.mu.Lock()
+	defer a.history.mu.Unlock()
+
+	if err := a.loadApplyHistoryLocked(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(a.getProfilesDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create settings directory: %v", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal apply history entry: %v", err)
+	}
+
+	file, err := os.OpenFile(a.getApplyHistoryPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open apply history: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write apply history: %v", err)
+	}
+
+	a.history.entries++
+	if entry.Outcome == ApplyOutcomeSuccess {
+		a.history.counts[entry.Profile]++
+	}
+
+	if a.history.entries > MAX_APPLY_HISTORY+APPLY_HISTORY_SLACK {
+		return a.trimApplyHistoryLocked()
+	}
+	return nil
+}
+
+// loadApplyHistoryLocked counts the entries and successful applies of the
+// history file the first time it is needed. The caller holds history.mu.
+func (a *App) loadApplyHistoryLocked() error {
+	if a.history.loaded {
+		return nil
+	}
+
+	entries, err := a.readApplyHistoryLocked()
+	if err != nil {
+		return err
+	}
+	a.history.count(entries)
+	a.history.loaded = true
+
+	return nil
+}
+
+// count replaces the cached counts with those of the given entries
+func (h *applyHistory) count(entries []ApplyHistoryEntry) {
+	h.entries = len(entries)
+	h.counts = make(map[string]int)
+	for _, entry := range entries {
+		if entry.Outcome == ApplyOutcomeSuccess {
+			h.counts[entry.Profile]++
+		}
+	}
+}
+
+// trimApplyHistoryLocked rewrites the history file with only its newest
+// MAX_APPLY_HISTORY entries. The caller holds history.mu.
+func (a *App) trimApplyHistoryLocked() error {
+	entries, err := a.readApplyHistoryLocked()
+	if err != nil {
+		return err
+	}
+	if len(entries) > MAX_APPLY_HISTORY {
+		entries = entries[len(entries)-MAX_APPLY_HISTORY:]
+	}
+
+	var data []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal apply history entry: %v", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	// Write to a temporary file first so a crash never leaves a partial history
+	historyPath := a.getApplyHistoryPath()
+	tempPath := historyPath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write apply history: %v", err)
+	}
+	if err := os.Rename(tempPath, historyPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to replace apply history: %v", err)
+	}
+
+	a.history.count(entries)
+	return nil
+}
+
+// readApplyHistory reads every entry of the history file, oldest first
+func (a *App) readApplyHistory() ([]ApplyHistoryEntry, error) {
+	a.history.mu.Lock()
+	defer a.history.mu.Unlock()
+
+	return a.readApplyHistoryLocked()
+}
+
+// readApplyHistoryLocked reads the history file, the caller holds history.mu.
+// Lines that cannot be parsed, such as a line cut short by a crash, are skipped.
+func (a *App) readApplyHistoryLocked() ([]ApplyHistoryEntry, error) {
+	file, err := os.Open(a.getApplyHistoryPath())
+	if os.IsNotExist(err) {
+		return []ApplyHistoryEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open apply history: %v", err)
+	}
+	defer file.Close()
+
+	entries := []ApplyHistoryEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry ApplyHistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read apply history: %v", err)
+	}
+
+	return entries, nil
+}
+
+// GetApplyHistory returns the recorded profile applications matching the
+// filter, newest first
+func (a *App) GetApplyHistory(filter ApplyHistoryFilter) ([]ApplyHistoryEntry, error) {
+	entries, err := a.readApplyHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []ApplyHistoryEntry{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !filter.matches(entries[i]) {
+			continue
+		}
+		matched = append(matched, entries[i])
+		if filter.Limit > 0 && len(matched) == filter.Limit {
+			break
+		}
+	}
+
+	return matched, nil
+}
+
+// profileNamesByUse returns the profile names with the most successfully
+// applied first, profiles used equally often keep their saved order. Only the
+// applies still in the history file are counted.
+func (a *App) profileNamesByUse() []string {
+	counts := make(map[string]int)
+	a.history.mu.Lock()
+	if err := a.loadApplyHistoryLocked(); err == nil {
+		for name, count := range a.history.counts {
+			counts[name] = count
+		}
+	}
+	a.history.mu.Unlock()
+
+	profiles := a.currentProfiles()
+	names := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return counts[names[i]] > counts[names[j]]
+	})
+
+	return names
+}
+
+// GetTrayOrder returns how the tray orders the profiles
+func (a *App) GetTrayOrder() string {
+	return a.currentSettings().TrayOrder
+}
+
+// SetTrayOrder changes how the tray orders the profiles
+func (a *App) SetTrayOrder(order string) error {
+	if order != TrayOrderSaved && order != TrayOrderMostUsed {
+		return fmt.Errorf("invalid tray order: %s", order)
+	}
+
+	err := a.updateSettings(func(s *Settings) error {
+		s.TrayOrder = order
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	a.sendProfilesUpdatedEvent()
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplyHistoryTrimAndMostUsed(t *testing.T) {
+	app, _ := newTestApp(t)
+	err := app.updateProfiles(func([]Profile) ([]Profile, error) {
+		return []Profile{{Name: "Desk"}, {Name: "TV"}, {Name: "Laptop"}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	appendEntry := func(i int, profile, outcome string) {
+		t.Helper()
+		entry := ApplyHistoryEntry{Time: start.Add(time.Duration(i) * time.Minute), Profile: profile, Trigger: TriggerCLI, Outcome: outcome}
+		if err := app.appendApplyHistory(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// All but the last 5 applies of Desk are trimmed away
+	total := MAX_APPLY_HISTORY + APPLY_HISTORY_SLACK + 1
+	for i := 0; i < total; i++ {
+		switch {
+		case i < APPLY_HISTORY_SLACK+6:
+			appendEntry(i, "Desk", ApplyOutcomeSuccess)
+		case i%2 == 0:
+			appendEntry(i, "TV", ApplyOutcomeSuccess)
+		default:
+			appendEntry(i, "Laptop", ApplyOutcomeFailure)
+		}
+	}
+
+	entries, err := app.readApplyHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MAX_APPLY_HISTORY {
+		t.Fatalf("history entries = %d, want %d", len(entries), MAX_APPLY_HISTORY)
+	}
+	if want := start.Add(time.Duration(total-1) * time.Minute); !entries[len(entries)-1].Time.Equal(want) {
+		t.Errorf("newest entry time = %v, want %v", entries[len(entries)-1].Time, want)
+	}
+
+	if got, want := app.profileNamesByUse(), []string{"TV", "Desk", "Laptop"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("profileNamesByUse() = %v, want %v", got, want)
+	}
+
+	if got := app.history.counts["Desk"]; got != 5 {
+		t.Errorf("Desk applies counted = %d, want the 5 left after trimming", got)
+	}
+
+	// The cached counts match what a fresh load of the trimmed file finds
+	cached := app.history.counts
+	app.history = &applyHistory{}
+	if got := app.profileNamesByUse(); !reflect.DeepEqual(got, []string{"TV", "Desk", "Laptop"}) {
+		t.Errorf("profileNamesByUse() after reload = %v", got)
+	}
+	if !reflect.DeepEqual(app.history.counts, cached) {
+		t.Errorf("reloaded counts = %v, want %v", app.history.counts, cached)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"monitor-profile-manager-wails/pkg/hotkeys"
+	"strings"
+)
+
+const (
+	TriggerHotkey = "hotkey"
+)
+
+// profileHotkeys returns the hotkey text of every profile with one
+func profileHotkeys(profiles []Profile) map[string]string {
+	bindings := make(map[string]string, len(profiles))
+	for _, profile := range profiles {
+		if profile.Hotkey != "" {
+			bindings[profile.Name] = profile.Hotkey
+		}
+	}
+	return bindings
+}
+
+// registerHotkeys registers the hotkeys of all profiles, replacing any
+// previously registered ones
+func (a *App) registerHotkeys() error {
+	if a.headless {
+		return nil
+	}
+
+	bindings, err := hotkeys.ParseBindings(profileHotkeys(a.currentProfiles()))
+	if err != nil {
+		return err
+	}
+	return a.hotkeys.SetBindings(bindings)
+}
+
+// applyProfileFromHotkey applies the profile bound to a pressed hotkey
+func (a *App) applyProfileFromHotkey(profileName string) {
+	a.autoApplyProfile(TriggerHotkey, profileName, "hotkey pressed")
+}
+
+// SetProfileHotkey assigns a key combination such as "Ctrl+Alt+1" to a profile.
+// An empty hotkey removes the binding.
+func (a *App) SetProfileHotkey(profileName string, hotkey string) error {
+	hotkey = strings.TrimSpace(hotkey)
+	if hotkey != "" {
+		combo, err := hotkeys.Parse(hotkey)
+		if err != nil {
+			return err
+		}
+		hotkey = combo.String()
+	}
+
+	err := a.updateProfiles(func(profiles []Profile) ([]Profile, error) {
+		index := profileIndex(profiles, profileName)
+		if index < 0 {
+			return nil, fmt.Errorf("profile not found: %s", profileName)
+		}
+
+		// Reject conflicts before anything is saved
+		bindings := profileHotkeys(profiles)
+		bindings[profileName] = hotkey
+		if _, err := hotkeys.ParseBindings(bindings); err != nil {
+			return nil, err
+		}
+
+		profiles[index].Hotkey = hotkey
+		return profiles, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.registerHotkeys()
+}
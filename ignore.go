@@ -0,0 +1,316 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+const (
+	IGNORE_LIST_FILE_NAME = "ignore_list.json"
+)
+
+// Ignore rule targets
+const (
+	IgnoreTargetMonitor = "monitor"
+	IgnoreTargetAudio   = "audio"
+)
+
+// Ignore rule match types
+const (
+	IgnoreMatchID    = "id"    // exact device ID (monitor ID or svcl command-line ID)
+	IgnoreMatchGlob  = "glob"  // glob against the device name or nickname, e.g. "NVIDIA Virtual Audio*"
+	IgnoreMatchRegex = "regex" // regular expression against the device name or nickname
+)
+
+// IgnoreRule hides every monitor or audio device matching the pattern
+type IgnoreRule struct {
+	Target  string `json:"target"`
+	Match   string `json:"match"`
+	Pattern string `json:"pattern"`
+
+	re *regexp.Regexp // compiled Pattern of regex rules, set by compile
+}
+
+type IgnoreList struct {
+	// AudioDevices holds exact audio device IDs from older versions. It is migrated
+	// into Rules when the ignore list is loaded.
+	AudioDevices []string     `json:"audioDevices,omitempty"`
+	Rules        []IgnoreRule `json:"rules"`
+}
+
+// compile checks that the rule can be evaluated and compiles regex patterns
+// so they are not compiled for every device
+func (r *IgnoreRule) compile() error {
+	if r.Target != IgnoreTargetMonitor && r.Target != IgnoreTargetAudio {
+		return fmt.Errorf("invalid ignore rule target: %s", r.Target)
+	}
+
+	if r.Pattern == "" {
+		return fmt.Errorf("ignore rule pattern cannot be empty")
+	}
+
+	switch r.Match {
+	case IgnoreMatchID:
+		return nil
+	case IgnoreMatchGlob:
+		if _, err := path.Match(r.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern %q: %v", r.Pattern, err)
+		}
+		return nil
+	case IgnoreMatchRegex:
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex pattern %q: %v", r.Pattern, err)
+		}
+		r.re = re
+		return nil
+	default:
+		return fmt.Errorf("invalid ignore rule match type: %s", r.Match)
+	}
+}
+
+// sameRule reports whether two rules have the same target, match type and pattern
+func sameRule(a, b IgnoreRule) bool {
+	return a.Target == b.Target && a.Match == b.Match && a.Pattern == b.Pattern
+}
+
+// matches reports whether the rule matches a device with the given ID and names.
+// Names include the device name and its nickname, empty names are skipped. A
+// regex rule that was not compiled never matches.
+func (r IgnoreRule) matches(id string, names ...string) bool {
+	if r.Match == IgnoreMatchID {
+		return r.Pattern == id
+	}
+
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		switch r.Match {
+		case IgnoreMatchGlob:
+			if matched, err := path.Match(r.Pattern, name); err == nil && matched {
+				return true
+			}
+		case IgnoreMatchRegex:
+			if r.re != nil && r.re.MatchString(name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// loadIgnoreList loads the ignore list from disk
+func (a *App) loadIgnoreList() {
+	ignoreListPath := a.getIgnoreListPath()
+	data, err := os.ReadFile(ignoreListPath)
+	if err != nil {
+		a.storeIgnoreRules([]IgnoreRule{})
+		return
+	}
+
+	var ignoreList IgnoreList
+	if err := json.Unmarshal(data, &ignoreList); err != nil {
+		a.storeIgnoreRules([]IgnoreRule{})
+		return
+	}
+
+	if ignoreList.Rules == nil {
+		ignoreList.Rules = []IgnoreRule{}
+	}
+
+	// Migrate exact audio device IDs saved by older versions
+	for _, deviceID := range ignoreList.AudioDevices {
+		ignoreList.Rules = append(ignoreList.Rules, IgnoreRule{
+			Target:  IgnoreTargetAudio,
+			Match:   IgnoreMatchID,
+			Pattern: deviceID,
+		})
+	}
+
+	for i := range ignoreList.Rules {
+		if err := ignoreList.Rules[i].compile(); err != nil {
+			slog.Warn("Ignore rule will not match", "error", err)
+		}
+	}
+
+	a.storeIgnoreRules(ignoreList.Rules)
+}
+
+// saveIgnoreList saves the ignore rules to disk
+func (a *App) saveIgnoreList(rules []IgnoreRule) error {
+	ignoreListPath := a.getIgnoreListPath()
+	data, err := json.MarshalIndent(IgnoreList{Rules: rules}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ignore list: %v", err)
+	}
+
+	if err := os.WriteFile(ignoreListPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to save ignore list: %v", err)
+	}
+
+	return nil
+}
+
+// currentIgnoreRules returns the ignore rules. The slice is shared and must
+// not be modified, change the rules with updateIgnoreRules.
+func (a *App) currentIgnoreRules() []IgnoreRule {
+	a.stateMu.RLock()
+	defer a.stateMu.RUnlock()
+
+	return a.ignoreRules
+}
+
+// storeIgnoreRules replaces the ignore rules
+func (a *App) storeIgnoreRules(rules []IgnoreRule) {
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+
+	a.ignoreRules = rules
+}
+
+// updateIgnoreRules replaces the ignore rules with the result of update,
+// which receives a copy of them, and saves them to disk. The rules are left
+// unchanged when update fails.
+func (a *App) updateIgnoreRules(update func(rules []IgnoreRule) ([]IgnoreRule, error)) error {
+	a.ignoreMu.Lock()
+	defer a.ignoreMu.Unlock()
+
+	rules, err := update(slices.Clone(a.currentIgnoreRules()))
+	if err != nil {
+		return err
+	}
+	a.storeIgnoreRules(rules)
+
+	return a.saveIgnoreList(rules)
+}
+
+// getIgnoreListPath returns the path where the ignore list is stored
+func (a *App) getIgnoreListPath() string {
+	profilesDir := a.getProfilesDir()
+	return filepath.Join(profilesDir, IGNORE_LIST_FILE_NAME)
+}
+
+// isAudioDeviceIgnored checks if an audio device matches any audio ignore rule
+func (a *App) isAudioDeviceIgnored(device AudioDevice) bool {
+	for _, rule := range a.currentIgnoreRules() {
+		if rule.Target == IgnoreTargetAudio && rule.matches(device.ID, device.Name, device.Nickname) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMonitorIgnored checks if a monitor matches any monitor ignore rule
+func (a *App) isMonitorIgnored(monitor Monitor) bool {
+	for _, rule := range a.currentIgnoreRules() {
+		if rule.Target == IgnoreTargetMonitor && rule.matches(monitor.MonitorId, monitor.DisplayName, monitor.Nickname) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterIgnoredMonitors returns the monitors that are not ignored
+func (a *App) filterIgnoredMonitors(monitors []Monitor) []Monitor {
+	filtered := make([]Monitor, 0, len(monitors))
+	for _, monitor := range monitors {
+		if !a.isMonitorIgnored(monitor) {
+			filtered = append(filtered, monitor)
+		}
+	}
+	return filtered
+}
+
+// filterIgnoredAudioDevices returns the audio devices that are not ignored
+func (a *App) filterIgnoredAudioDevices(devices []AudioDevice) []AudioDevice {
+	filtered := make([]AudioDevice, 0, len(devices))
+	for _, device := range devices {
+		if !a.isAudioDeviceIgnored(device) {
+			filtered = append(filtered, device)
+		}
+	}
+	return filtered
+}
+
+// indexOfIgnoreRule returns the position of the rule in rules or -1
+func indexOfIgnoreRule(rules []IgnoreRule, rule IgnoreRule) int {
+	return slices.IndexFunc(rules, func(existing IgnoreRule) bool {
+		return sameRule(existing, rule)
+	})
+}
+
+// GetIgnoreRules returns every configured ignore rule
+func (a *App) GetIgnoreRules() []IgnoreRule {
+	return slices.Clone(a.currentIgnoreRules())
+}
+
+// AddIgnoreRule validates and adds a rule to the ignore list
+func (a *App) AddIgnoreRule(rule IgnoreRule) error {
+	return a.addIgnoreRule(rule, "ignore rule already exists")
+}
+
+// addIgnoreRule adds a rule unless an identical rule exists, in which case
+// it fails with the duplicate message
+func (a *App) addIgnoreRule(rule IgnoreRule, duplicate string) error {
+	if err := rule.compile(); err != nil {
+		return err
+	}
+
+	return a.updateIgnoreRules(func(rules []IgnoreRule) ([]IgnoreRule, error) {
+		if indexOfIgnoreRule(rules, rule) >= 0 {
+			return nil, errors.New(duplicate)
+		}
+		return append(rules, rule), nil
+	})
+}
+
+// RemoveIgnoreRule removes a rule from the ignore list
+func (a *App) RemoveIgnoreRule(rule IgnoreRule) error {
+	return a.removeIgnoreRule(rule, "ignore rule not found")
+}
+
+// removeIgnoreRule removes a rule, failing with the missing message when the
+// rule is not in the ignore list
+func (a *App) removeIgnoreRule(rule IgnoreRule, missing string) error {
+	return a.updateIgnoreRules(func(rules []IgnoreRule) ([]IgnoreRule, error) {
+		i := indexOfIgnoreRule(rules, rule)
+		if i < 0 {
+			return nil, errors.New(missing)
+		}
+		return slices.Delete(rules, i, i+1), nil
+	})
+}
+
+// IgnoreAudioDevice adds an exact audio device ID rule to the ignore list
+func (a *App) IgnoreAudioDevice(deviceID string) error {
+	rule := IgnoreRule{Target: IgnoreTargetAudio, Match: IgnoreMatchID, Pattern: deviceID}
+	return a.addIgnoreRule(rule, "device is already ignored")
+}
+
+// UnignoreAudioDevice removes an exact audio device ID rule from the ignore list
+func (a *App) UnignoreAudioDevice(deviceID string) error {
+	rule := IgnoreRule{Target: IgnoreTargetAudio, Match: IgnoreMatchID, Pattern: deviceID}
+	return a.removeIgnoreRule(rule, "device is not in ignore list")
+}
+
+// IgnoreMonitor adds an exact monitor ID rule to the ignore list
+func (a *App) IgnoreMonitor(monitorId string) error {
+	rule := IgnoreRule{Target: IgnoreTargetMonitor, Match: IgnoreMatchID, Pattern: monitorId}
+	return a.addIgnoreRule(rule, "monitor is already ignored")
+}
+
+// UnignoreMonitor removes an exact monitor ID rule from the ignore list
+func (a *App) UnignoreMonitor(monitorId string) error {
+	rule := IgnoreRule{Target: IgnoreTargetMonitor, Match: IgnoreMatchID, Pattern: monitorId}
+	return a.removeIgnoreRule(rule, "monitor is not in ignore list")
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	app, _ := newTestApp(t)
+	app.loadIgnoreList()
+
+	rules := []IgnoreRule{
+		{Target: IgnoreTargetAudio, Match: IgnoreMatchGlob, Pattern: "NVIDIA Virtual Audio*"},
+		{Target: IgnoreTargetAudio, Match: IgnoreMatchRegex, Pattern: `(?i)^steam streaming`},
+		{Target: IgnoreTargetMonitor, Match: IgnoreMatchID, Pattern: "GSM5B7F"},
+		{Target: IgnoreTargetMonitor, Match: IgnoreMatchRegex, Pattern: `^Projector \d+$`},
+	}
+	for _, rule := range rules {
+		if err := app.AddIgnoreRule(rule); err != nil {
+			t.Fatalf("AddIgnoreRule(%+v) error = %v", rule, err)
+		}
+	}
+
+	if err := app.AddIgnoreRule(rules[1]); err == nil {
+		t.Error("AddIgnoreRule() accepted a duplicate rule")
+	}
+	for _, invalid := range []IgnoreRule{
+		{Target: IgnoreTargetAudio, Match: IgnoreMatchRegex, Pattern: "("},
+		{Target: IgnoreTargetAudio, Match: IgnoreMatchGlob, Pattern: "["},
+		{Target: "keyboard", Match: IgnoreMatchID, Pattern: "x"},
+		{Target: IgnoreTargetAudio, Match: "prefix", Pattern: "x"},
+		{Target: IgnoreTargetAudio, Match: IgnoreMatchID, Pattern: ""},
+	} {
+		if err := app.AddIgnoreRule(invalid); err == nil {
+			t.Errorf("AddIgnoreRule(%+v) accepted an invalid rule", invalid)
+		}
+	}
+
+	check := func(app *App) {
+		t.Helper()
+
+		audio := map[AudioDevice]bool{
+			{ID: "1", Name: "NVIDIA Virtual Audio Device"}:           true,
+			{ID: "2", Name: "Steam Streaming Speakers"}:              true,
+			{ID: "3", Name: "Speakers", Nickname: "STEAM STREAMING"}: true,
+			{ID: "4", Name: "Speakers"}:                              false,
+		}
+		for device, want := range audio {
+			if got := app.isAudioDeviceIgnored(device); got != want {
+				t.Errorf("isAudioDeviceIgnored(%+v) = %t, want %t", device, got, want)
+			}
+		}
+
+		monitors := map[Monitor]bool{
+			{MonitorId: "GSM5B7F", DisplayName: "LG"}:          true,
+			{MonitorId: "DEL4321", DisplayName: "Projector 2"}: true,
+			{MonitorId: "DEL4321", DisplayName: "Projector"}:   false,
+		}
+		for monitor, want := range monitors {
+			if got := app.isMonitorIgnored(monitor); got != want {
+				t.Errorf("isMonitorIgnored(%+v) = %t, want %t", monitor, got, want)
+			}
+		}
+	}
+	check(app)
+
+	// Rules loaded from disk are compiled as well
+	reloaded := NewApp()
+	reloaded.loadIgnoreList()
+	check(reloaded)
+
+	// Callers get a copy of the rules
+	app.GetIgnoreRules()[0].Pattern = "changed"
+	if got := app.GetIgnoreRules()[0].Pattern; got != rules[0].Pattern {
+		t.Errorf("first rule pattern = %q after changing the returned copy, want %q", got, rules[0].Pattern)
+	}
+
+	if err := app.RemoveIgnoreRule(rules[1]); err != nil {
+		t.Fatal(err)
+	}
+	if app.isAudioDeviceIgnored(AudioDevice{ID: "2", Name: "Steam Streaming Speakers"}) {
+		t.Error("removed regex rule still matches")
+	}
+}
+
+func TestLoadIgnoreListSkipsInvalidRegex(t *testing.T) {
+	app, _ := newTestApp(t)
+	data := `{"audioDevices": ["{0.0.0.00000000}.{legacy}"], "rules": [{"target": "audio", "match": "regex", "pattern": "("}]}`
+	if err := os.WriteFile(app.getIgnoreListPath(), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app.loadIgnoreList()
+
+	if len(app.GetIgnoreRules()) != 2 {
+		t.Fatalf("rules = %+v, want the invalid rule and the migrated ID", app.GetIgnoreRules())
+	}
+	if app.isAudioDeviceIgnored(AudioDevice{ID: "1", Name: "("}) {
+		t.Error("invalid regex rule matched")
+	}
+	if !app.isAudioDeviceIgnored(AudioDevice{ID: "{0.0.0.00000000}.{legacy}", Name: "Speakers"}) {
+		t.Error("migrated device ID is not ignored")
+	}
+}
+
+func TestGetAudioDevicesHidesIgnoredDevices(t *testing.T) {
+	app, tools := newTestApp(t)
+	tools.setAudio(
+		fakeAudioDevice{id: "Speakers", name: "Speakers", state: "Active", isDefault: true},
+		fakeAudioDevice{id: "Virtual", name: "NVIDIA Virtual Audio Device", state: "Active"},
+	)
+	if err := app.AddIgnoreRule(IgnoreRule{Target: IgnoreTargetAudio, Match: IgnoreMatchGlob, Pattern: "NVIDIA Virtual Audio*"}); err != nil {
+		t.Fatal(err)
+	}
+
+	devices := app.GetAudioDevices()
+	if len(devices) != 1 || devices[0].ID != "Speakers" {
+		t.Errorf("GetAudioDevices() = %+v, want only Speakers", devices)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"monitor-profile-manager-wails/pkg/audio"
+	"monitor-profile-manager-wails/pkg/monitors"
+)
+
+const (
+	TriggerLaunch = "launch"
+	TriggerLogin  = "login"
+)
+
+// LaunchOptions are the command line options that affect startup
+type LaunchOptions struct {
+	Minimized    bool   // start hidden with --minimized
+	ApplyProfile string // profile given with --apply
+	AtLogin      bool   // started by autostart with --login
+	Once         bool   // apply and exit with --once
+}
+
+// newLaunchFlagSet defines the launch flags. The same set parses the command
+// line and the arguments forwarded by a second launch.
+func newLaunchFlagSet(name string, errorHandling flag.ErrorHandling) (*flag.FlagSet, *LaunchOptions) {
+	options := &LaunchOptions{}
+	flags := flag.NewFlagSet(name, errorHandling)
+	flags.BoolVar(&options.Minimized, "minimized", false, "Start the application minimized")
+	flags.StringVar(&options.ApplyProfile, "apply", "", "Apply the named profile on startup")
+	flags.BoolVar(&options.AtLogin, "login", false, "Started at login, applies the login profile unless --apply is given")
+	flags.BoolVar(&options.Once, "once", false, "Apply the profile and exit without opening the window or tray")
+	return flags, options
+}
+
+// launchProfile returns the profile to apply for the given options and the
+// trigger it is reported as. --apply wins over the login profile.
+func (a *App) launchProfile(options LaunchOptions) (string, string) {
+	if options.ApplyProfile != "" {
+		return options.ApplyProfile, TriggerLaunch
+	}
+	if loginProfile := a.currentSettings().LoginProfile; options.AtLogin && loginProfile != "" {
+		return loginProfile, TriggerLogin
+	}
+	return "", ""
+}
+
+// applyLaunchProfile applies the profile requested on the command line or the
+// login profile when started by autostart
+func (a *App) applyLaunchProfile() {
+	profileName, trigger := a.launchProfile(a.launch)
+	if profileName == "" {
+		return
+	}
+
+	a.autoApplyProfile(trigger, profileName, fmt.Sprintf("applied on %s", trigger))
+}
+
+// initHeadless loads what is needed to apply profiles without the window,
+// tray or background watchers
+func (a *App) initHeadless() error {
+	a.headless = true
+
+	// Without the embedded tools, the configured paths and PATH are used
+	toolsDir, err := a.extractTools()
+	if err != nil {
+		slog.Warn("Failed to extract tools", "error", err)
+	}
+	a.audioTools = audio.NewAudioTools(toolsDir, a.toolAudit, a.toolVerifier)
+	a.monitorTools = monitors.NewMonitorTools(toolsDir, a.toolAudit, a.toolVerifier)
+
+	a.loadSettings()
+	a.loadIgnoreList()
+	a.loadNicknames()
+	a.readProfiles()
+	a.loadUndoStack()
+	return nil
+}
+
+// runOnce applies the launch profile and returns the process exit code. It is
+// used by --once to apply a profile from scripts or Task Scheduler.
+func (a *App) runOnce() int {
+	defer a.removeToolsTempDir()
+
+	if err := a.initHeadless(); err != nil {
+		fmt.Println(err)
+		return EXIT_ERROR
+	}
+	defer a.webhooks.Drain(WEBHOOK_DRAIN_TIMEOUT)
+
+	profileName, trigger := a.launchProfile(a.launch)
+	if profileName == "" {
+		fmt.Println("--once needs a profile from --apply or a login profile with --login")
+		return EXIT_USAGE
+	}
+
+	if err := a.applyProfileFrom(trigger, profileName); err != nil {
+		fmt.Printf("Failed to apply profile %s: %v\n", profileName, err)
+		return EXIT_ERROR
+	}
+
+	fmt.Printf("Applied profile %s\n", profileName)
+	return EXIT_OK
+}
+
+// GetLoginProfile returns the profile applied when started at login
+func (a *App) GetLoginProfile() string {
+	return a.currentSettings().LoginProfile
+}
+
+// SetLoginProfile sets the profile applied when started at login, an empty
+// name disables it
+func (a *App) SetLoginProfile(profileName string) error {
+	if profileName != "" && a.findProfile(profileName) == nil {
+		return fmt.Errorf("profile not found: %s", profileName)
+	}
+
+	return a.updateSettings(func(s *Settings) error {
+		s.LoginProfile = profileName
+		return nil
+	})
+}
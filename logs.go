@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"monitor-profile-manager-wails/pkg/logging"
+)
+
+const (
+	LOG_DIRECTORY     = "logs"
+	LOG_FILE_NAME     = "app.log"
+	LOG_MAX_SIZE      = 1024 * 1024 // rotate after 1 MiB
+	LOG_MAX_FILES     = 5
+	LOG_MAX_RECENT    = 500 // entries kept in memory for GetRecentLogs
+	DEFAULT_LOG_LEVEL = "info"
+)
+
+// getLogPath returns the path of the current log file
+func (a *App) getLogPath() string {
+	return filepath.Join(a.getProfilesDir(), LOG_DIRECTORY, LOG_FILE_NAME)
+}
+
+// startLogging sends all logging to the rotating log file in the data
+// directory, and to console when it is set
+func (a *App) startLogging(console io.Writer) {
+	logs, err := logging.New(logging.Options{
+		Path:      a.getLogPath(),
+		MaxSize:   LOG_MAX_SIZE,
+		MaxFiles:  LOG_MAX_FILES,
+		MaxRecent: LOG_MAX_RECENT,
+		Console:   console,
+	})
+
+	a.logs = logs
+	a.applyLogLevel()
+	slog.SetDefault(logs.Slog())
+
+	if err != nil {
+		slog.Warn("Logging to memory only", "error", err)
+	}
+}
+
+// applyLogLevel sets the level from the settings
+func (a *App) applyLogLevel() {
+	level, err := logging.ParseLevel(a.currentSettings().LogLevel)
+	if err != nil {
+		level = slog.LevelInfo
+	}
+	a.logs.SetLevel(level)
+}
+
+// GetLogLevel returns the minimum level that is logged
+func (a *App) GetLogLevel() string {
+	return strings.ToLower(a.logs.Level().String())
+}
+
+// SetLogLevel changes the minimum level that is logged: debug, info, warn or error
+func (a *App) SetLogLevel(level string) error {
+	if _, err := logging.ParseLevel(level); err != nil {
+		return err
+	}
+
+	err := a.updateSettings(func(s *Settings) error {
+		s.LogLevel = strings.ToLower(strings.TrimSpace(level))
+		return nil
+	})
+	a.applyLogLevel()
+	return err
+}
+
+// GetRecentLogs returns up to limit of the most recent log entries at or above
+// minLevel, oldest first
+func (a *App) GetRecentLogs(minLevel string, limit int) ([]logging.Entry, error) {
+	level := slog.LevelDebug
+	if minLevel != "" {
+		var err error
+		if level, err = logging.ParseLevel(minLevel); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit < 0 {
+		return nil, fmt.Errorf("invalid limit: %d", limit)
+	}
+
+	return a.logs.Recent(level, limit), nil
+}
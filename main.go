@@ -5,12 +5,14 @@ import (
 	"embed"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime/debug"
 
 	"monitor-profile-manager-wails/pkg/common"
+	"monitor-profile-manager-wails/pkg/integrity"
 
 	"fyne.io/systray"
 	"github.com/wailsapp/wails/v2"
@@ -22,11 +24,18 @@ import (
 //go:embed all:frontend/dist
 //go:embed tools/svcl/svcl.exe
 //go:embed tools/multimonitortool/MultiMonitorTool.exe
+//go:embed tools/*
 //go:embed build/windows/icon.ico
 var assets embed.FS
 
+// The tool checksums are recorded from the downloaded tools at build time.
+// tools/SHA256SUMS is not committed, tools/* embeds it when it was generated
+// so the tree also builds before go generate has run.
+//go:generate go run tools/checksums.go
+
 var profilesUpdatedCh = make(chan []string)
 var profileSelectedCh = make(chan string)
+var automationPausedCh = make(chan bool, 1) // the latest state for the tray, see sendAutomationPaused
 
 // getIconBytes returns the icon bytes for the system tray
 func getIconBytes() []byte {
@@ -38,28 +47,44 @@ func getIconBytes() []byte {
 	return iconData
 }
 
-// extractTools extracts embedded tools to a temporary directory
-func extractTools() (string, error) {
+// embeddedTools lists the embedded tools and where they are extracted to
+var embeddedTools = []struct {
+	srcPath  string
+	destPath string
+}{
+	{"tools/svcl/svcl.exe", "svcl/svcl.exe"},
+	{"tools/multimonitortool/MultiMonitorTool.exe", "multimonitortool/MultiMonitorTool.exe"},
+}
+
+// extractTools extracts embedded tools to a cache under the data directory,
+// keyed by their recorded hashes so later runs reuse them. Every tool is
+// checked against the SHA-256 recorded at build time before it is written and
+// registered with the verifier, which checks it again before every run. When
+// the cache cannot be created the tools go to a temporary directory that is
+// removed on shutdown.
+func (a *App) extractTools() (string, error) {
 	// Check if we're in development mode (wails dev)
 	if common.IsDevelopmentMode() {
 		return "", nil
 	}
 
-	tempDir, err := os.MkdirTemp("", "monitor-profile-tools")
+	checksums, err := toolChecksums()
 	if err != nil {
 		return "", err
 	}
 
-	// List of embedded tools to extract
-	tools := []struct {
-		srcPath  string
-		destPath string
-	}{
-		{"tools/svcl/svcl.exe", "svcl/svcl.exe"},
-		{"tools/multimonitortool/MultiMonitorTool.exe", "multimonitortool/MultiMonitorTool.exe"},
+	removeStaleToolsTempDirs()
+
+	toolsDir, err := a.prepareToolCache(checksums)
+	if err != nil {
+		slog.Warn("Extracting tools to a temporary directory", "error", err)
+		if toolsDir, err = createToolsTempDir(); err != nil {
+			return "", err
+		}
+		a.toolsTempDir = toolsDir
 	}
 
-	for _, tool := range tools {
+	for _, tool := range embeddedTools {
 		// Read embedded file
 		data, err := assets.ReadFile(tool.srcPath)
 		if err != nil {
@@ -67,40 +92,125 @@ func extractTools() (string, error) {
 		}
 
 		// Create destination directory
-		destDir := filepath.Join(tempDir, filepath.Dir(tool.destPath))
+		destDir := filepath.Join(toolsDir, filepath.Dir(tool.destPath))
 		if err := os.MkdirAll(destDir, 0755); err != nil {
 			return "", fmt.Errorf("failed to create directory %s: %v", destDir, err)
 		}
 
-		// Write file with executable permissions
-		destFile := filepath.Join(tempDir, tool.destPath)
+		destFile := filepath.Join(toolsDir, tool.destPath)
+		trusted := integrity.Tool{
+			Name:     filepath.Base(tool.destPath),
+			Path:     destFile,
+			Expected: checksums[tool.destPath],
+			Restore:  func() error { return writeTool(destFile, data) },
+		}
+
+		// Never extract a tool that differs from the recorded checksums, e.g.
+		// when the tool was replaced without running go generate again
+		if err := verifyEmbeddedTool(trusted, data); err != nil {
+			slog.Error("Refusing embedded tool", "tool", trusted.Name, "error", err)
+			a.toolVerifier.Refuse(trusted, err)
+			continue
+		}
 
-		// Remove existing file to handle multiple runs
-		os.Remove(destFile) // Ignore error if file doesn't exist
+		// Extract tools that are not cached yet
+		if _, err := os.Stat(destFile); os.IsNotExist(err) {
+			if err := writeTool(destFile, data); err != nil {
+				return "", err
+			}
+		}
 
-		if err := os.WriteFile(destFile, data, 0755); err != nil {
-			return "", fmt.Errorf("failed to write %s: %v", destFile, err)
+		// Verify the cached file, a mismatch is extracted once more
+		if err := a.toolVerifier.Add(trusted); err != nil {
+			slog.Error("Extracted tool failed verification", "tool", trusted.Name, "error", err)
 		}
 	}
 
-	return tempDir, nil
+	return toolsDir, nil
+}
+
+// writeTool writes a tool with executable permissions. The file is written
+// next to the destination and renamed, so a concurrent run never sees a
+// partial tool.
+func writeTool(destFile string, data []byte) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(destFile), filepath.Base(destFile)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", destFile, err)
+	}
+	defer os.Remove(tempFile.Name()) // Ignore error once renamed
+
+	_, err = tempFile.Write(data)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFile.Name(), 0755)
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), destFile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", destFile, err)
+	}
+	return nil
 }
 
 func main() {
 	// Parse command line flags
-	startMinimized := flag.Bool("minimized", false, "Start the application minimized")
-	flag.Parse()
+	flags, launchOptions := newLaunchFlagSet(os.Args[0], flag.ExitOnError)
+	flags.Parse(os.Args[1:])
+
+	// Create an instance of the app structure
+	app := NewApp()
+	app.launch = *launchOptions
+
+	// Log to the data directory. Subcommands and one-shot runs keep the
+	// console for their own output.
+	var console io.Writer = os.Stderr
+	if flags.NArg() > 0 || launchOptions.Once {
+		console = nil
+	}
+	app.startLogging(console)
+	app.startToolAudit()
+	defer app.logs.Close()
 
 	// Set up panic recovery to prevent crashes
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Application panic recovered: %v", r)
-			log.Printf("Stack trace: %s", debug.Stack())
+			slog.Error("Application panic recovered", "panic", r, "stack", string(debug.Stack()))
 		}
 	}()
 
-	// Create an instance of the app structure
-	app := NewApp()
+	// Subcommands run headless without creating the window or tray
+	if flags.NArg() > 0 {
+		attachConsole()
+
+		command := findCLICommand(flags.Arg(0))
+		if command == nil {
+			fmt.Fprintf(os.Stderr, "Unknown command: %s\n", flags.Arg(0))
+			printCLIUsage(os.Stderr)
+			os.Exit(EXIT_USAGE)
+		}
+		os.Exit(app.runCLI(command, flags.Args()[1:]))
+	}
+
+	// One-shot mode applies the profile without creating the window or tray
+	if launchOptions.Once {
+		attachConsole()
+		os.Exit(app.runOnce())
+	}
+
+	// Hand the arguments to an already running instance instead of starting a
+	// second window and tray
+	if forwarded, err := app.claimInstance(os.Args[1:]); forwarded {
+		if err != nil {
+			slog.Error("Running instance failed to handle arguments", "error", err)
+			os.Exit(EXIT_ERROR)
+		}
+		os.Exit(EXIT_OK)
+	} else if err != nil {
+		slog.Warn("Single instance check failed, continuing", "error", err)
+	}
 
 	// Set up system tray
 	systrayStart, systrayEnd := systray.RunWithExternalLoop(func() {
@@ -111,6 +221,8 @@ func main() {
 
 		// Add menu items
 		mApplyProfile := systray.AddMenuItem("Apply Profile", "Apply a profile")
+		mRevert := systray.AddMenuItem("Revert to previous", "Restore the setup from before the last applied profile")
+		mPauseAutomation := systray.AddMenuItemCheckbox("Pause Automation", "Stop applying profiles automatically", false)
 		mShow := systray.AddMenuItem("Show", "Show main window")
 		mQuit := systray.AddMenuItem("Quit", "Quit application")
 
@@ -122,6 +234,10 @@ func main() {
 				select {
 				case <-mShow.ClickedCh:
 					runtime.WindowShow(app.ctx)
+				case <-mRevert.ClickedCh:
+					if err := app.UndoLastApply(); err != nil {
+						slog.Error("Failed to revert to previous state", "error", err)
+					}
 				case <-mQuit.ClickedCh:
 					systray.Quit()
 					if app.ctx != nil {
@@ -130,7 +246,27 @@ func main() {
 					return
 
 				case profile := <-profileSelectedCh:
-					app.ApplyProfile(profile)
+					app.applyProfileFrom(TriggerTray, profile)
+				}
+			}
+		}()
+
+		// handle automation pause toggles
+		go func() {
+			for range mPauseAutomation.ClickedCh {
+				if err := app.SetAutomationPaused(!mPauseAutomation.Checked()); err != nil {
+					slog.Error("Failed to toggle automation", "error", err)
+				}
+			}
+		}()
+
+		// keep the pause checkbox in sync with the automation state
+		go func() {
+			for paused := range automationPausedCh {
+				if paused {
+					mPauseAutomation.Check()
+				} else {
+					mPauseAutomation.Uncheck()
 				}
 			}
 		}()
@@ -162,7 +298,7 @@ func main() {
 			}
 		}()
 	}, func() {
-		fmt.Println("System tray exiting")
+		slog.Info("System tray exiting")
 	})
 
 	// Clean up system tray
@@ -181,6 +317,7 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
 		Bind: []interface{}{
 			app,
 		},
@@ -188,10 +325,10 @@ func main() {
 			runtime.WindowHide(ctx)
 			return true
 		},
-		StartHidden: *startMinimized,
+		StartHidden: launchOptions.Minimized,
 	})
 
 	if err != nil {
-		println("Error:", err.Error())
+		slog.Error("Application failed", "error", err)
 	}
 }
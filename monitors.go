@@ -35,9 +35,9 @@ func (a *App) SetMonitorEnabledState(monitorId string, active bool) error {
 func (a *App) SetMonitorPrimary(monitorId string) error {
 	// Find the monitor and update primary status
 	var monitor *Monitor
-	for i := range a.monitors {
-		if a.monitors[i].MonitorId == monitorId {
-			monitor = &a.monitors[i]
+	for _, m := range a.currentMonitors() {
+		if m.MonitorId == monitorId {
+			monitor = &m
 			break
 		}
 	}
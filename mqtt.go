@@ -0,0 +1,308 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	MQTT_DEFAULT_TOPIC_PREFIX     = "windows-profile-manager"
+	MQTT_DEFAULT_DISCOVERY_PREFIX = "homeassistant"
+	MQTT_PUBLISH_TIMEOUT          = 10 * time.Second
+	MQTT_DEVICE_PUBLISH_DELAY     = time.Second
+	TriggerMQTT                   = "mqtt"
+)
+
+// MQTTSettings configures the MQTT broker connection
+type MQTTSettings struct {
+	Enabled         bool   `json:"enabled"`
+	Broker          string `json:"broker"` // e.g. "tcp://homeassistant.local:1883"
+	Username        string `json:"username"`
+	Password        string `json:"password"`
+	TopicPrefix     string `json:"topicPrefix"`     // topics are <prefix>/<node id>/...
+	Discovery       bool   `json:"discovery"`       // publish Home Assistant discovery payloads
+	DiscoveryPrefix string `json:"discoveryPrefix"` // Home Assistant discovery prefix
+}
+
+// mqttBridge holds the broker connection while MQTT is enabled
+type mqttBridge struct {
+	mu      sync.Mutex
+	client  mqtt.Client
+	nodeID  string
+	devices debouncer
+}
+
+var mqttNodeIDPattern = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// mqttNodeID identifies this machine in topics and discovery payloads
+func mqttNodeID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "pc"
+	}
+	return mqttNodeIDPattern.ReplaceAllString(strings.ToLower(hostname), "_")
+}
+
+// mqttTopic returns the topic below this machine's base topic
+func (a *App) mqttTopic(suffix string) string {
+	return fmt.Sprintf("%s/%s/%s", a.currentSettings().MQTT.TopicPrefix, a.mqtt.nodeID, suffix)
+}
+
+// startMQTT connects to the broker when MQTT is enabled. The connection is
+// retried in the background, so an unreachable broker does not block startup.
+func (a *App) startMQTT() error {
+	settings := a.currentSettings().MQTT
+	if !settings.Enabled {
+		return nil
+	}
+
+	if settings.Broker == "" {
+		return fmt.Errorf("no MQTT broker configured")
+	}
+
+	a.mqtt.mu.Lock()
+	defer a.mqtt.mu.Unlock()
+
+	if a.mqtt.client != nil {
+		return nil
+	}
+
+	a.mqtt.nodeID = mqttNodeID()
+
+	options := mqtt.NewClientOptions().
+		AddBroker(settings.Broker).
+		SetClientID(MQTT_DEFAULT_TOPIC_PREFIX+"-"+a.mqtt.nodeID).
+		SetUsername(settings.Username).
+		SetPassword(settings.Password).
+		SetWill(a.mqttTopic("availability"), "offline", 1, true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOnConnectHandler(a.handleMQTTConnect).
+		SetConnectionLostHandler(func(client mqtt.Client, err error) {
+			slog.Warn("MQTT connection lost", "error", err)
+		})
+
+	a.mqtt.client = mqtt.NewClient(options)
+	a.mqtt.client.Connect()
+
+	return nil
+}
+
+// stopMQTT marks this machine offline and disconnects from the broker
+func (a *App) stopMQTT() {
+	a.mqtt.mu.Lock()
+	client := a.mqtt.client
+	a.mqtt.client = nil
+	a.mqtt.mu.Unlock()
+
+	a.mqtt.devices.stop()
+
+	if client == nil {
+		return
+	}
+
+	if client.IsConnected() {
+		token := client.Publish(a.mqttTopic("availability"), 1, true, "offline")
+		token.WaitTimeout(MQTT_PUBLISH_TIMEOUT)
+	}
+	client.Disconnect(250)
+}
+
+// mqttPublish publishes a payload without blocking the caller, failures are logged
+func (a *App) mqttPublish(topic string, payload interface{}, retained bool) {
+	a.mqtt.mu.Lock()
+	client := a.mqtt.client
+	a.mqtt.mu.Unlock()
+
+	if client == nil || !client.IsConnected() {
+		return
+	}
+
+	if _, ok := payload.(string); !ok {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			slog.Error("MQTT: failed to marshal payload", "topic", topic, "error", err)
+			return
+		}
+		payload = data
+	}
+
+	token := client.Publish(topic, 1, retained, payload)
+	go func() {
+		if !token.WaitTimeout(MQTT_PUBLISH_TIMEOUT) {
+			slog.Warn("MQTT: timed out publishing", "topic", topic)
+		} else if err := token.Error(); err != nil {
+			slog.Error("MQTT: failed to publish", "topic", topic, "error", err)
+		}
+	}()
+}
+
+// handleMQTTConnect subscribes to the command topic and publishes the full
+// state, it runs again after every reconnect
+func (a *App) handleMQTTConnect(client mqtt.Client) {
+	token := client.Subscribe(a.mqttTopic("profile/set"), 1, a.handleMQTTCommand)
+	go func() {
+		if token.WaitTimeout(MQTT_PUBLISH_TIMEOUT) && token.Error() != nil {
+			slog.Error("MQTT: failed to subscribe to commands", "error", token.Error())
+		}
+	}()
+
+	a.mqttPublish(a.mqttTopic("availability"), "online", true)
+	a.publishMQTTProfiles()
+	a.publishMQTTProfileState()
+	go a.publishMQTTDevices()
+}
+
+// handleMQTTCommand applies the profile named in a command message
+func (a *App) handleMQTTCommand(client mqtt.Client, message mqtt.Message) {
+	profileName := strings.TrimSpace(string(message.Payload()))
+	if profileName == "" {
+		return
+	}
+
+	// Applying takes a while, keep the client's message handling free
+	go a.autoApplyProfile(TriggerMQTT, profileName, "MQTT command")
+}
+
+// handleMQTTEvent keeps the published state in sync with application events
+func (a *App) handleMQTTEvent(event Event) {
+	a.mqtt.mu.Lock()
+	connected := a.mqtt.client != nil
+	a.mqtt.mu.Unlock()
+
+	if !connected {
+		return
+	}
+
+	switch {
+	case event.Type == EventProfileCreated || event.Type == EventProfileDeleted:
+		a.publishMQTTProfiles()
+	case strings.HasPrefix(event.Type, "profile:"):
+		a.publishMQTTProfileState()
+	case strings.HasPrefix(event.Type, "monitor:") || strings.HasPrefix(event.Type, "audio:"):
+		// Hotplugs arrive in bursts, publish once they settle
+		a.mqtt.devices.trigger(MQTT_DEVICE_PUBLISH_DELAY, a.publishMQTTDevices)
+	}
+}
+
+// publishMQTTProfileState publishes the name of the active profile
+func (a *App) publishMQTTProfileState() {
+	a.mqttPublish(a.mqttTopic("profile/state"), a.currentSettings().LastAppliedProfile, true)
+}
+
+// publishMQTTProfiles publishes the profile names and, with discovery enabled,
+// the Home Assistant entities whose options depend on them
+func (a *App) publishMQTTProfiles() {
+	profiles := a.currentProfiles()
+	names := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+	}
+
+	a.mqttPublish(a.mqttTopic("profiles"), names, true)
+
+	if a.currentSettings().MQTT.Discovery {
+		a.publishMQTTDiscovery(names)
+	}
+}
+
+// publishMQTTDevices publishes the current monitors and audio devices
+func (a *App) publishMQTTDevices() {
+	if monitors, err := a.readMonitors(); err != nil {
+		slog.Error("MQTT: failed to read monitors", "error", err)
+	} else {
+		a.mqttPublish(a.mqttTopic("monitors"), a.filterIgnoredMonitors(monitors), true)
+	}
+
+	devices, err := a.readVisibleAudioDevices()
+	if err != nil {
+		slog.Error("MQTT: failed to read audio devices", "error", err)
+		return
+	}
+
+	a.mqttPublish(a.mqttTopic("audio"), devices, true)
+
+	defaultDevice := ""
+	for _, device := range devices {
+		if device.IsDefault {
+			defaultDevice = device.Name
+			if device.Nickname != "" {
+				defaultDevice = device.Nickname
+			}
+		}
+	}
+	a.mqttPublish(a.mqttTopic("audio/default"), defaultDevice, true)
+}
+
+// publishMQTTDiscovery publishes Home Assistant discovery payloads for a
+// profile select and a default audio device sensor
+func (a *App) publishMQTTDiscovery(profileNames []string) {
+	nodeID := a.mqtt.nodeID
+	hostname, _ := os.Hostname()
+
+	device := map[string]interface{}{
+		"identifiers": []string{MQTT_DEFAULT_TOPIC_PREFIX + "_" + nodeID},
+		"name":        fmt.Sprintf("Windows Profile Manager (%s)", hostname),
+		"model":       "Windows Profile Manager",
+	}
+	availability := a.mqttTopic("availability")
+	discoveryPrefix := a.currentSettings().MQTT.DiscoveryPrefix
+
+	a.mqttPublish(fmt.Sprintf("%s/select/%s/profile/config", discoveryPrefix, nodeID), map[string]interface{}{
+		"name":               "Profile",
+		"unique_id":          nodeID + "_profile",
+		"icon":               "mdi:monitor-multiple",
+		"state_topic":        a.mqttTopic("profile/state"),
+		"command_topic":      a.mqttTopic("profile/set"),
+		"availability_topic": availability,
+		"options":            profileNames,
+		"device":             device,
+	}, true)
+
+	a.mqttPublish(fmt.Sprintf("%s/sensor/%s/default_audio/config", discoveryPrefix, nodeID), map[string]interface{}{
+		"name":               "Default audio device",
+		"unique_id":          nodeID + "_default_audio",
+		"icon":               "mdi:speaker",
+		"state_topic":        a.mqttTopic("audio/default"),
+		"availability_topic": availability,
+		"device":             device,
+	}, true)
+}
+
+// GetMQTTSettings returns the MQTT settings
+func (a *App) GetMQTTSettings() MQTTSettings {
+	return a.currentSettings().MQTT
+}
+
+// SetMQTTSettings updates the MQTT settings and reconnects to the broker
+func (a *App) SetMQTTSettings(settings MQTTSettings) error {
+	settings.Broker = strings.TrimSpace(settings.Broker)
+	if settings.Enabled && settings.Broker == "" {
+		return fmt.Errorf("an MQTT broker is required")
+	}
+	if settings.TopicPrefix == "" {
+		settings.TopicPrefix = MQTT_DEFAULT_TOPIC_PREFIX
+	}
+	if settings.DiscoveryPrefix == "" {
+		settings.DiscoveryPrefix = MQTT_DEFAULT_DISCOVERY_PREFIX
+	}
+
+	a.stopMQTT()
+	err := a.updateSettings(func(s *Settings) error {
+		s.MQTT = settings
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.startMQTT()
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+)
+
+const mqttTestTimeout = 5 * time.Second
+
+// testBroker is an embedded broker that records every published message
+type testBroker struct {
+	server   *mqttserver.Server
+	address  string
+	mu       sync.Mutex
+	messages map[string][]string // topic -> payloads in publish order
+}
+
+// startTestBroker starts a broker on a free loopback port
+func startTestBroker(t *testing.T) *testBroker {
+	t.Helper()
+
+	broker := &testBroker{
+		server: mqttserver.New(&mqttserver.Options{
+			InlineClient: true,
+			Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}),
+		messages: make(map[string][]string),
+	}
+	if err := broker.server.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	listener := listeners.NewTCP(listeners.Config{ID: "test", Address: "127.0.0.1:0"})
+	if err := broker.server.AddListener(listener); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.server.Serve(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { broker.server.Close() })
+	broker.address = "tcp://" + listener.Address()
+
+	err := broker.server.Subscribe("#", 1, func(cl *mqttserver.Client, sub packets.Subscription, pk packets.Packet) {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		broker.messages[pk.TopicName] = append(broker.messages[pk.TopicName], string(pk.Payload))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return broker
+}
+
+// last returns the last payload published to topic
+func (b *testBroker) last(topic string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	payloads := b.messages[topic]
+	if len(payloads) == 0 {
+		return "", false
+	}
+	return payloads[len(payloads)-1], true
+}
+
+// waitForPayload waits until payload is the last message on topic
+func (b *testBroker) waitForPayload(t *testing.T, topic string, payload string) {
+	t.Helper()
+	waitFor(t, mqttTestTimeout, fmt.Sprintf("%q on %s", payload, topic), func() bool {
+		last, ok := b.last(topic)
+		return ok && last == payload
+	})
+}
+
+// startTestMQTT connects app to broker and disconnects it when the test ends
+func startTestMQTT(t *testing.T, app *App, broker *testBroker) {
+	t.Helper()
+
+	err := app.SetMQTTSettings(MQTTSettings{
+		Enabled:         true,
+		Broker:          broker.address,
+		TopicPrefix:     MQTT_DEFAULT_TOPIC_PREFIX,
+		Discovery:       true,
+		DiscoveryPrefix: MQTT_DEFAULT_DISCOVERY_PREFIX,
+	})
+	if err != nil {
+		t.Fatalf("SetMQTTSettings() error = %v", err)
+	}
+	t.Cleanup(app.stopMQTT)
+}
+
+func TestMQTTPublishesDiscovery(t *testing.T) {
+	app, _ := newTestApp(t)
+	if err := app.saveCurrentProfile("Desk"); err != nil {
+		t.Fatal(err)
+	}
+
+	broker := startTestBroker(t)
+	startTestMQTT(t, app, broker)
+
+	broker.waitForPayload(t, app.mqttTopic("availability"), "online")
+	broker.waitForPayload(t, app.mqttTopic("profiles"), `["Desk"]`)
+
+	selectTopic := fmt.Sprintf("%s/select/%s/profile/config", MQTT_DEFAULT_DISCOVERY_PREFIX, app.mqtt.nodeID)
+	waitFor(t, mqttTestTimeout, "profile select discovery", func() bool {
+		_, ok := broker.last(selectTopic)
+		return ok
+	})
+
+	payload, _ := broker.last(selectTopic)
+	var discovery struct {
+		CommandTopic      string   `json:"command_topic"`
+		StateTopic        string   `json:"state_topic"`
+		AvailabilityTopic string   `json:"availability_topic"`
+		Options           []string `json:"options"`
+	}
+	if err := json.Unmarshal([]byte(payload), &discovery); err != nil {
+		t.Fatalf("invalid discovery payload %q: %v", payload, err)
+	}
+	if discovery.CommandTopic != app.mqttTopic("profile/set") {
+		t.Errorf("command_topic = %q, want %q", discovery.CommandTopic, app.mqttTopic("profile/set"))
+	}
+	if discovery.StateTopic != app.mqttTopic("profile/state") {
+		t.Errorf("state_topic = %q, want %q", discovery.StateTopic, app.mqttTopic("profile/state"))
+	}
+	if discovery.AvailabilityTopic != app.mqttTopic("availability") {
+		t.Errorf("availability_topic = %q, want %q", discovery.AvailabilityTopic, app.mqttTopic("availability"))
+	}
+	if len(discovery.Options) != 1 || discovery.Options[0] != "Desk" {
+		t.Errorf("options = %v, want [Desk]", discovery.Options)
+	}
+
+	sensorTopic := fmt.Sprintf("%s/sensor/%s/default_audio/config", MQTT_DEFAULT_DISCOVERY_PREFIX, app.mqtt.nodeID)
+	waitFor(t, mqttTestTimeout, "default audio discovery", func() bool {
+		_, ok := broker.last(sensorTopic)
+		return ok
+	})
+}
+
+func TestMQTTProfileSetAppliesProfile(t *testing.T) {
+	app, tools := newTestApp(t)
+	if err := app.saveCurrentProfile("Desk"); err != nil {
+		t.Fatal(err)
+	}
+
+	broker := startTestBroker(t)
+	startTestMQTT(t, app, broker)
+	broker.waitForPayload(t, app.mqttTopic("availability"), "online")
+
+	// The subscription is made in the connect handler, retry until it is in place
+	loadConfig := "/LoadConfig " + app.getMonitorConfigPath("Desk")
+	waitFor(t, mqttTestTimeout, "the profile to be applied", func() bool {
+		broker.server.Publish(app.mqttTopic("profile/set"), []byte("Desk"), false, 1)
+		time.Sleep(50 * time.Millisecond)
+		return tools.called("MultiMonitorTool.exe", loadConfig)
+	})
+
+	broker.waitForPayload(t, app.mqttTopic("profile/state"), "Desk")
+
+	history, err := app.GetApplyHistory(ApplyHistoryFilter{Trigger: TriggerMQTT})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) == 0 || history[0].Profile != "Desk" {
+		t.Errorf("apply history = %+v, want an MQTT apply of Desk", history)
+	}
+}
+
+func TestMQTTAvailability(t *testing.T) {
+	app, _ := newTestApp(t)
+	broker := startTestBroker(t)
+	startTestMQTT(t, app, broker)
+
+	availability := app.mqttTopic("availability")
+	broker.waitForPayload(t, availability, "online")
+
+	// Dropping the connection without a disconnect publishes the will, the
+	// client then reconnects and is online again
+	client, ok := broker.server.Clients.Get(MQTT_DEFAULT_TOPIC_PREFIX + "-" + app.mqtt.nodeID)
+	if !ok {
+		t.Fatal("client not connected to the broker")
+	}
+	client.Stop(fmt.Errorf("connection dropped by test"))
+
+	broker.waitForPayload(t, availability, "offline")
+	broker.waitForPayload(t, availability, "online")
+
+	// Stopping marks the machine offline
+	app.stopMQTT()
+	broker.waitForPayload(t, availability, "offline")
+}
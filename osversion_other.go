@@ -0,0 +1,10 @@
+//go:build !windows
+
+package main
+
+import "runtime"
+
+// osVersion describes the operating system, only Windows reports a version
+func osVersion() string {
+	return runtime.GOOS
+}
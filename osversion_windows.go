@@ -0,0 +1,15 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// osVersion describes the running Windows version, e.g. "Windows 10.0 build 22631"
+func osVersion() string {
+	version := windows.RtlGetVersion()
+	return fmt.Sprintf("Windows %d.%d build %d", version.MajorVersion, version.MinorVersion, version.BuildNumber)
+}
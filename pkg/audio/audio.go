@@ -3,13 +3,12 @@ package audio
 import (
 	"encoding/csv"
 	"fmt"
+	"monitor-profile-manager-wails/pkg/audit"
 	"monitor-profile-manager-wails/pkg/common"
+	"monitor-profile-manager-wails/pkg/integrity"
 	"os"
 	"os/exec"
-	"path/filepath"
-	"runtime"
 	"strings"
-	"syscall"
 )
 
 // Column name constants
@@ -22,14 +21,45 @@ const (
 	SvclExe          = "svcl.exe"
 )
 
+// Device state values reported by svcl in the "Device State" column
+const (
+	StateActive     = "Active"
+	StateDisabled   = "Disabled"
+	StateUnplugged  = "Unplugged"
+	StateNotPresent = "Not Present"
+)
+
 // AudioTools manages audio device operations with configurable tools directory
 type AudioTools struct {
 	toolsDir string
+	recorder audit.Recorder       // records every tool invocation, may be nil
+	checker  integrity.Checker    // verifies the tool before every run, may be nil
+	override *common.ToolOverride // svcl.exe configured in the settings, shared with WithOrigin
+}
+
+// NewAudioTools creates a new AudioTools instance with the specified tools directory.
+// Every tool invocation is passed to recorder and checker is consulted before
+// every run.
+func NewAudioTools(toolsDir string, recorder audit.Recorder, checker integrity.Checker) *AudioTools {
+	return &AudioTools{toolsDir: toolsDir, recorder: recorder, checker: checker, override: &common.ToolOverride{}}
+}
+
+// WithOrigin returns tools that share the configured path with a and
+// record every invocation with the given origin, e.g. audit.OriginPoll
+func (a *AudioTools) WithOrigin(origin string) *AudioTools {
+	tools := *a
+	tools.recorder = audit.WithOrigin(a.recorder, origin)
+	return &tools
 }
 
-// NewAudioTools creates a new AudioTools instance with the specified tools directory
-func NewAudioTools(toolsDir string) *AudioTools {
-	return &AudioTools{toolsDir: toolsDir}
+// run verifies the tool and runs the command, recording the invocation
+func (a *AudioTools) run(cmd *exec.Cmd) ([]byte, error) {
+	if a.checker != nil {
+		if err := a.checker.Check(cmd.Path); err != nil {
+			return nil, err
+		}
+	}
+	return audit.Run(a.recorder, cmd)
 }
 
 // AudioDeviceInfo represents information about an audio device
@@ -56,37 +86,37 @@ func (a AudioDeviceInfo) IsPrimary() bool {
 
 // Helper method to check if device is active
 func (a AudioDeviceInfo) IsActive() bool {
-	return strings.Contains(strings.ToLower(a.data[ColDeviceState]), "active")
+	return a.data[ColDeviceState] == StateActive
 }
 
-// hideConsoleCommand creates a command with hidden console window on Windows
-func (a *AudioTools) hideConsoleCommand(name string, args ...string) *exec.Cmd {
-	cmd := exec.Command(name, args...)
-
-	// Hide console window on Windows
-	if runtime.GOOS == "windows" {
-		cmd.SysProcAttr = &syscall.SysProcAttr{
-			HideWindow: true,
-		}
-	}
-
-	return cmd
+// Helper method to check if device is enabled. Unplugged and not present
+// devices are still enabled, they are just not currently available.
+func (a AudioDeviceInfo) IsEnabled() bool {
+	return a.data[ColDeviceState] != StateDisabled
 }
 
 // GetSvclPath returns the full path to svcl.exe
 func (a *AudioTools) GetSvclPath() (string, error) {
-	// If custom tools directory is set (embedded tools), use it
-	if a.toolsDir != "" {
-		return filepath.Join(a.toolsDir, "svcl", SvclExe), nil
-	}
+	path, _, err := a.ResolveSvclPath()
+	return path, err
+}
+
+// ResolveSvclPath returns the path of svcl.exe and where it was found, one of
+// the common.ToolSource constants
+func (a *AudioTools) ResolveSvclPath() (string, string, error) {
+	override := a.override.Get()
 
-	// Check if we're in development mode (wails dev)
-	if common.IsDevelopmentMode() {
-		// Development mode: use relative path from project root
-		return filepath.Join("tools", "svcl", SvclExe), nil
+	var refused func(string) bool
+	if a.checker != nil {
+		refused = a.checker.Refused
 	}
+	return common.ResolveTool(SvclExe, override, a.toolsDir, "svcl", refused)
+}
 
-	return "", fmt.Errorf("svcl.exe not found in tools/svcl directory")
+// SetSvclPath sets a svcl.exe that is used instead of the embedded one, an
+// empty path removes the override
+func (a *AudioTools) SetSvclPath(path string) {
+	a.override.Set(path)
 }
 
 // CheckSvclExists verifies that svcl.exe exists
@@ -97,13 +127,13 @@ func (a *AudioTools) CheckSvclExists() error {
 	}
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return fmt.Errorf("svcl.exe not found in tools/svcl directory")
+		return fmt.Errorf("svcl.exe not found at %s", path)
 	}
 	return nil
 }
 
-// GetActiveOutputDevices retrieves active output devices using svcl.exe /scomma
-func (a *AudioTools) GetActiveOutputDevices() ([]AudioDeviceInfo, error) {
+// GetOutputDevicesCSV returns the raw CSV output of svcl.exe /scomma
+func (a *AudioTools) GetOutputDevicesCSV() ([]byte, error) {
 	// Check if svcl.exe exists
 	if err := a.CheckSvclExists(); err != nil {
 		return nil, err
@@ -117,10 +147,20 @@ func (a *AudioTools) GetActiveOutputDevices() ([]AudioDeviceInfo, error) {
 
 	// Execute svcl.exe with /scomma and capture stdout
 	cmd := a.hideConsoleCommand(toolPath, "/scomma")
-	output, err := cmd.Output()
+	output, err := a.run(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute svcl.exe: %w", err)
 	}
+	return output, nil
+}
+
+// GetOutputDevices retrieves every output device known to Windows using svcl.exe /scomma,
+// regardless of its state (active, disabled, unplugged or not present)
+func (a *AudioTools) GetOutputDevices() ([]AudioDeviceInfo, error) {
+	output, err := a.GetOutputDevicesCSV()
+	if err != nil {
+		return nil, err
+	}
 
 	// Parse the CSV output from stdout
 	reader := csv.NewReader(strings.NewReader(string(output)))
@@ -140,7 +180,7 @@ func (a *AudioTools) GetActiveOutputDevices() ([]AudioDeviceInfo, error) {
 
 	var devices []AudioDeviceInfo
 
-	// Filter for active output devices
+	// Filter for output devices
 	for i, row := range records {
 		if i == 0 {
 			// Skip header row
@@ -154,23 +194,18 @@ func (a *AudioTools) GetActiveOutputDevices() ([]AudioDeviceInfo, error) {
 
 		// Get values for filtering
 		direction := ""
-		deviceState := ""
 		deviceType := ""
 
 		if idx, exists := colIndexes["Direction"]; exists && idx < len(row) {
 			direction = strings.TrimSpace(row[idx])
 		}
 
-		if idx, exists := colIndexes["Device State"]; exists && idx < len(row) {
-			deviceState = strings.TrimSpace(row[idx])
-		}
-
 		if idx, exists := colIndexes["Type"]; exists && idx < len(row) {
 			deviceType = strings.TrimSpace(row[idx])
 		}
 
-		// Apply filters: Direction == "Render", State == "Active", Type == "Device"
-		if direction == "Render" && deviceState == "Active" && deviceType == "Device" {
+		// Apply filters: Direction == "Render", Type == "Device"
+		if direction == "Render" && deviceType == "Device" {
 			// Create device info with relevant data
 			deviceData := make(map[string]string)
 
@@ -202,9 +237,51 @@ func (a *AudioTools) SetPrimaryDevice(commandLineId string) error {
 	}
 
 	cmd := a.hideConsoleCommand(toolPath, "/SetDefault", commandLineId, "all")
-	err = cmd.Run()
+	_, err = a.run(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to set primary audio device: %w", err)
 	}
 	return nil
 }
+
+// EnableDevice enables the specified audio device
+func (a *AudioTools) EnableDevice(commandLineId string) error {
+	// Check if svcl.exe exists
+	if err := a.CheckSvclExists(); err != nil {
+		return err
+	}
+
+	// Get svcl.exe path
+	toolPath, err := a.GetSvclPath()
+	if err != nil {
+		return err
+	}
+
+	cmd := a.hideConsoleCommand(toolPath, "/Enable", commandLineId)
+	_, err = a.run(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to enable audio device: %w", err)
+	}
+	return nil
+}
+
+// DisableDevice disables the specified audio device
+func (a *AudioTools) DisableDevice(commandLineId string) error {
+	// Check if svcl.exe exists
+	if err := a.CheckSvclExists(); err != nil {
+		return err
+	}
+
+	// Get svcl.exe path
+	toolPath, err := a.GetSvclPath()
+	if err != nil {
+		return err
+	}
+
+	cmd := a.hideConsoleCommand(toolPath, "/Disable", commandLineId)
+	_, err = a.run(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to disable audio device: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package audio
+
+import "os/exec"
+
+// hideConsoleCommand creates a command, there is no console to hide
+func (a *AudioTools) hideConsoleCommand(name string, args ...string) *exec.Cmd {
+	return exec.Command(name, args...)
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package audio
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// hideConsoleCommand creates a command with hidden console window
+func (a *AudioTools) hideConsoleCommand(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow: true,
+	}
+
+	return cmd
+}
@@ -0,0 +1,192 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// MaxOutput is the number of leading stdout and stderr bytes kept per invocation
+const MaxOutput = 1024
+
+// OriginPoll marks invocations of the background device polling. Invocations
+// without an origin were started by an apply, a save, the UI, the CLI or an API.
+const OriginPoll = "poll"
+
+// Invocation records a single run of an external tool
+type Invocation struct {
+	Time       time.Time `json:"time"`
+	Origin     string    `json:"origin,omitempty"` // OriginPoll or empty
+	Tool       string    `json:"tool"`             // executable name, e.g. "svcl.exe"
+	Path       string    `json:"path"`
+	Args       []string  `json:"args"`
+	DurationMs int64     `json:"durationMs"`
+	ExitCode   int       `json:"exitCode"` // -1 when the tool could not be started
+	Stdout     string    `json:"stdout,omitempty"`
+	Stderr     string    `json:"stderr,omitempty"`
+	Truncated  bool      `json:"truncated,omitempty"` // stdout or stderr was cut to MaxOutput bytes
+	Error      string    `json:"error,omitempty"`
+}
+
+// Recorder receives every tool invocation
+type Recorder interface {
+	Record(invocation Invocation)
+}
+
+// originRecorder sets the origin of every invocation it passes on
+type originRecorder struct {
+	recorder Recorder
+	origin   string
+}
+
+// WithOrigin returns a recorder that sets the origin of every invocation
+// before passing it to recorder. It returns nil when recorder is nil.
+func WithOrigin(recorder Recorder, origin string) Recorder {
+	if recorder == nil {
+		return nil
+	}
+	return originRecorder{recorder: recorder, origin: origin}
+}
+
+// Record implements Recorder
+func (r originRecorder) Record(invocation Invocation) {
+	invocation.Origin = r.origin
+	r.recorder.Record(invocation)
+}
+
+// Run runs the command, records the invocation when recorder is set and
+// returns the complete stdout
+func Run(recorder Recorder, cmd *exec.Cmd) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+
+	if recorder != nil {
+		invocation := Invocation{
+			Time:       start,
+			Tool:       filepath.Base(cmd.Path),
+			Path:       cmd.Path,
+			Args:       append([]string{}, cmd.Args[1:]...),
+			DurationMs: time.Since(start).Milliseconds(),
+			ExitCode:   -1,
+		}
+		if cmd.ProcessState != nil {
+			invocation.ExitCode = cmd.ProcessState.ExitCode()
+		}
+		if err != nil {
+			invocation.Error = err.Error()
+		}
+
+		var cut bool
+		invocation.Stdout, cut = truncate(stdout.Bytes())
+		invocation.Truncated = cut
+		invocation.Stderr, cut = truncate(stderr.Bytes())
+		invocation.Truncated = invocation.Truncated || cut
+
+		recorder.Record(invocation)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// truncate returns at most MaxOutput bytes of output as a string
+func truncate(output []byte) (string, bool) {
+	if len(output) <= MaxOutput {
+		return string(output), false
+	}
+	return string(output[:MaxOutput]), true
+}
+
+// Trail keeps the most recent invocations in memory and appends them as JSON
+// lines to a writer, such as a rotating log file. Polls are kept apart from
+// the other invocations so they never push those out, and only failed polls
+// are written.
+type Trail struct {
+	mu       sync.Mutex
+	recent   []Invocation
+	polls    []Invocation
+	max      int
+	maxPolls int
+	persist  io.Writer
+}
+
+// NewTrail creates a trail that keeps the last max invocations and the last
+// maxPolls polls. persist may be nil.
+func NewTrail(max, maxPolls int, persist io.Writer) *Trail {
+	return &Trail{max: max, maxPolls: maxPolls, persist: persist}
+}
+
+// Record implements Recorder
+func (t *Trail) Record(invocation Invocation) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if invocation.Origin == OriginPoll {
+		t.polls = keepLast(append(t.polls, invocation), t.maxPolls)
+		if invocation.Error == "" {
+			return
+		}
+	} else {
+		t.recent = keepLast(append(t.recent, invocation), t.max)
+	}
+
+	if t.persist != nil {
+		if data, err := json.Marshal(invocation); err == nil {
+			t.persist.Write(append(data, '\n'))
+		}
+	}
+}
+
+// keepLast returns the last max invocations
+func keepLast(invocations []Invocation, max int) []Invocation {
+	if len(invocations) > max {
+		return invocations[len(invocations)-max:]
+	}
+	return invocations
+}
+
+// SetPersist changes where invocations are written, nil stops writing them
+func (t *Trail) SetPersist(persist io.Writer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.persist = persist
+}
+
+// Recent returns the kept invocations, oldest first. Polls are only included
+// with includePolls.
+func (t *Trail) Recent(includePolls bool) []Invocation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !includePolls {
+		return append([]Invocation{}, t.recent...)
+	}
+
+	// Merge both lists, which are each in the order they were recorded
+	merged := make([]Invocation, 0, len(t.recent)+len(t.polls))
+	i, j := 0, 0
+	for i < len(t.recent) && j < len(t.polls) {
+		if t.polls[j].Time.Before(t.recent[i].Time) {
+			merged = append(merged, t.polls[j])
+			j++
+		} else {
+			merged = append(merged, t.recent[i])
+			i++
+		}
+	}
+	merged = append(merged, t.recent[i:]...)
+	return append(merged, t.polls[j:]...)
+}
@@ -1,6 +1,12 @@
 package common
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+)
 
 // IsDevelopmentMode checks if the application is running in development mode
 // by looking for go.mod file in the current directory
@@ -8,3 +14,64 @@ func IsDevelopmentMode() bool {
 	_, err := os.Stat("go.mod")
 	return err == nil
 }
+
+// Where an external tool was found
+const (
+	ToolSourceOverride    = "override"    // configured in the settings
+	ToolSourceEmbedded    = "embedded"    // extracted from the executable
+	ToolSourceDevelopment = "development" // tools directory of the project
+	ToolSourcePath        = "path"        // found on PATH
+)
+
+// ToolOverride holds the path of a tool configured in the settings. The zero
+// value has no override.
+type ToolOverride struct {
+	mu   sync.RWMutex
+	path string
+}
+
+// Get returns the configured path, empty when there is none
+func (o *ToolOverride) Get() string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return o.path
+}
+
+// Set changes the configured path, an empty path removes the override
+func (o *ToolOverride) Set(path string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.path = path
+}
+
+// ResolveTool returns the path of an external tool and where it was found. A
+// configured override wins and must exist, then the extracted tools in
+// toolsDir/subDir, the project in development mode and finally PATH. An
+// extracted tool that is missing or for which refused reports true is skipped.
+// refused may be nil.
+func ResolveTool(exe string, override string, toolsDir string, subDir string, refused func(path string) bool) (string, string, error) {
+	if override != "" {
+		if _, err := os.Stat(override); err != nil {
+			return "", ToolSourceOverride, fmt.Errorf("configured %s not found at %s", exe, override)
+		}
+		return override, ToolSourceOverride, nil
+	}
+
+	if toolsDir != "" {
+		path := filepath.Join(toolsDir, subDir, exe)
+		if _, err := os.Stat(path); err == nil && (refused == nil || !refused(path)) {
+			return path, ToolSourceEmbedded, nil
+		}
+	} else if IsDevelopmentMode() {
+		// Development mode: use relative path from project root
+		return filepath.Join("tools", subDir, exe), ToolSourceDevelopment, nil
+	}
+
+	if path, err := exec.LookPath(exe); err == nil {
+		return path, ToolSourcePath, nil
+	}
+
+	return "", "", fmt.Errorf("%s not found in the tools directory, the configured path or PATH", exe)
+}
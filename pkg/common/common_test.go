@@ -0,0 +1,55 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveToolFallsBackToPath(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	pathDir := t.TempDir()
+	onPath := filepath.Join(pathDir, "tool.exe")
+	if err := os.WriteFile(onPath, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", pathDir)
+
+	toolsDir := t.TempDir()
+	embedded := filepath.Join(toolsDir, "tool", "tool.exe")
+
+	// The embedded copy was not extracted
+	path, source, err := ResolveTool("tool.exe", "", toolsDir, "tool", nil)
+	if err != nil || path != onPath || source != ToolSourcePath {
+		t.Errorf("missing embedded tool: ResolveTool() = %s, %s, %v, want %s from PATH", path, source, err, onPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(embedded), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(embedded, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, source, err = ResolveTool("tool.exe", "", toolsDir, "tool", func(string) bool { return false })
+	if err != nil || path != embedded || source != ToolSourceEmbedded {
+		t.Errorf("embedded tool: ResolveTool() = %s, %s, %v, want %s", path, source, err, embedded)
+	}
+
+	refused := func(p string) bool { return p == embedded }
+	path, source, err = ResolveTool("tool.exe", "", toolsDir, "tool", refused)
+	if err != nil || path != onPath || source != ToolSourcePath {
+		t.Errorf("refused embedded tool: ResolveTool() = %s, %s, %v, want %s from PATH", path, source, err, onPath)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, _, err := ResolveTool("tool.exe", "", toolsDir, "tool", refused); err == nil {
+		t.Error("ResolveTool() found a refused tool that is not on PATH")
+	}
+
+	// A configured override is never skipped
+	if _, source, err := ResolveTool("tool.exe", filepath.Join(pathDir, "missing.exe"), toolsDir, "tool", nil); err == nil || source != ToolSourceOverride {
+		t.Errorf("missing override: ResolveTool() = %s, %v, want an override error", source, err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package common
+
+import "fmt"
+
+// FileVersion is only supported on Windows
+func FileVersion(path string) (string, error) {
+	return "", fmt.Errorf("reading the version of %s is only supported on Windows", path)
+}
@@ -0,0 +1,37 @@
+//go:build windows
+
+package common
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// FileVersion returns the file version from the version resource of an
+// executable, e.g. "2.10.0.0"
+func FileVersion(path string) (string, error) {
+	size, err := windows.GetFileVersionInfoSize(path, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to read version of %s: %w", path, err)
+	}
+
+	info := make([]byte, size)
+	if err := windows.GetFileVersionInfo(path, 0, size, unsafe.Pointer(&info[0])); err != nil {
+		return "", fmt.Errorf("failed to read version of %s: %w", path, err)
+	}
+
+	var fixed *windows.VS_FIXEDFILEINFO
+	var length uint32
+	if err := windows.VerQueryValue(unsafe.Pointer(&info[0]), `\`, unsafe.Pointer(&fixed), &length); err != nil {
+		return "", fmt.Errorf("failed to read version of %s: %w", path, err)
+	}
+	if fixed == nil || length == 0 {
+		return "", fmt.Errorf("%s has no version information", path)
+	}
+
+	return fmt.Sprintf("%d.%d.%d.%d",
+		fixed.FileVersionMS>>16, fixed.FileVersionMS&0xffff,
+		fixed.FileVersionLS>>16, fixed.FileVersionLS&0xffff), nil
+}
@@ -0,0 +1,52 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxOutput is the number of trailing output bytes kept for error messages
+	MaxOutput = 2048
+	// waitDelay bounds the wait for output pipes held open by child processes
+	// after the hook was killed
+	waitDelay = 2 * time.Second
+)
+
+// Runner runs hook commands through the system shell
+type Runner struct{}
+
+// NewRunner creates a new Runner instance
+func NewRunner() *Runner {
+	return &Runner{}
+}
+
+// Run runs command with env added to the environment and kills it after
+// timeout. The combined output is returned, trimmed to the last MaxOutput bytes.
+func (r *Runner) Run(command string, env []string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := r.hideConsoleCommand(ctx, command)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.WaitDelay = waitDelay
+
+	output, err := cmd.CombinedOutput()
+	text := strings.TrimSpace(string(output))
+	if len(text) > MaxOutput {
+		text = text[len(text)-MaxOutput:]
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return text, fmt.Errorf("hook timed out after %s", timeout)
+	}
+	if err != nil {
+		return text, fmt.Errorf("hook failed: %w", err)
+	}
+
+	return text, nil
+}
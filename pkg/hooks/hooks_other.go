@@ -0,0 +1,13 @@
+//go:build !windows
+
+package hooks
+
+import (
+	"context"
+	"os/exec"
+)
+
+// hideConsoleCommand runs the hook through sh, there is no console to hide
+func (r *Runner) hideConsoleCommand(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
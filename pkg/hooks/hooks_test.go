@@ -0,0 +1,50 @@
+//go:build !windows
+
+package hooks
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunPassesEnvironment(t *testing.T) {
+	output, err := NewRunner().Run(`echo "$WPM_PROFILE"`, []string{"WPM_PROFILE=Desk"}, time.Second)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if output != "Desk" {
+		t.Errorf("Run() output = %q, want %q", output, "Desk")
+	}
+}
+
+func TestRunReportsFailure(t *testing.T) {
+	output, err := NewRunner().Run("echo broken; exit 3", nil, time.Second)
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Fatalf("Run() error = %v, want exit status 3", err)
+	}
+	if output != "broken" {
+		t.Errorf("Run() output = %q, want %q", output, "broken")
+	}
+}
+
+func TestRunTimesOut(t *testing.T) {
+	start := time.Now()
+	_, err := NewRunner().Run("sleep 5", nil, 100*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Run() error = %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Run() took %s after the timeout", elapsed)
+	}
+}
+
+func TestRunTrimsOutput(t *testing.T) {
+	output, err := NewRunner().Run("head -c 5000 /dev/zero | tr '\\0' x", nil, time.Second)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(output) != MaxOutput {
+		t.Errorf("len(output) = %d, want %d", len(output), MaxOutput)
+	}
+}
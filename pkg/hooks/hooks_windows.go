@@ -0,0 +1,22 @@
+//go:build windows
+
+package hooks
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+)
+
+// hideConsoleCommand creates a shell command with hidden console window. The
+// command line is passed to cmd.exe unchanged so quoting in the hook works as
+// typed.
+func (r *Runner) hideConsoleCommand(ctx context.Context, command string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "cmd.exe")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow: true,
+		CmdLine:    `cmd.exe /S /C "` + command + `"`,
+	}
+
+	return cmd
+}
@@ -0,0 +1,116 @@
+package hotkeys
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Modifier flags, matching the MOD_* values of the Win32 RegisterHotKey API
+const (
+	ModAlt   uint32 = 0x0001
+	ModCtrl  uint32 = 0x0002
+	ModShift uint32 = 0x0004
+	ModWin   uint32 = 0x0008
+)
+
+var modifierNames = map[string]uint32{
+	"CTRL":    ModCtrl,
+	"CONTROL": ModCtrl,
+	"ALT":     ModAlt,
+	"SHIFT":   ModShift,
+	"WIN":     ModWin,
+}
+
+// Virtual key codes of the named keys, letters and digits are added in init
+var keyCodes = map[string]uint32{
+	"SPACE":    0x20,
+	"ENTER":    0x0D,
+	"TAB":      0x09,
+	"ESC":      0x1B,
+	"PAGEUP":   0x21,
+	"PAGEDOWN": 0x22,
+	"END":      0x23,
+	"HOME":     0x24,
+	"LEFT":     0x25,
+	"UP":       0x26,
+	"RIGHT":    0x27,
+	"DOWN":     0x28,
+	"INSERT":   0x2D,
+	"DELETE":   0x2E,
+}
+
+func init() {
+	for c := 'A'; c <= 'Z'; c++ {
+		keyCodes[string(c)] = uint32(c)
+	}
+	for c := '0'; c <= '9'; c++ {
+		keyCodes[string(c)] = uint32(c)
+		keyCodes["NUMPAD"+string(c)] = 0x60 + uint32(c-'0')
+	}
+	for i := 1; i <= 24; i++ {
+		keyCodes[fmt.Sprintf("F%d", i)] = 0x70 + uint32(i-1)
+	}
+}
+
+// Combination is a parsed key combination such as Ctrl+Alt+1
+type Combination struct {
+	Modifiers uint32
+	Key       string // upper case key name, e.g. "1", "F5", "PAGEUP"
+	KeyCode   uint32 // Win32 virtual key code
+}
+
+// Parse parses a key combination like "Ctrl+Alt+1" or "shift+win+F5". At least
+// one modifier is required so global hotkeys do not swallow normal typing.
+func Parse(text string) (Combination, error) {
+	parts := strings.Split(strings.ReplaceAll(text, " ", ""), "+")
+	if len(parts) < 2 {
+		return Combination{}, fmt.Errorf("invalid hotkey %q: expected modifiers and a key, e.g. Ctrl+Alt+1", text)
+	}
+
+	var combo Combination
+	for _, part := range parts[:len(parts)-1] {
+		mod, ok := modifierNames[strings.ToUpper(part)]
+		if !ok {
+			return Combination{}, fmt.Errorf("invalid hotkey %q: unknown modifier %q", text, part)
+		}
+		if combo.Modifiers&mod != 0 {
+			return Combination{}, fmt.Errorf("invalid hotkey %q: modifier %q used twice", text, part)
+		}
+		combo.Modifiers |= mod
+	}
+
+	key := strings.ToUpper(parts[len(parts)-1])
+	code, ok := keyCodes[key]
+	if !ok {
+		return Combination{}, fmt.Errorf("invalid hotkey %q: unknown key %q", text, parts[len(parts)-1])
+	}
+	combo.Key = key
+	combo.KeyCode = code
+
+	return combo, nil
+}
+
+// String returns the canonical form, e.g. "Ctrl+Alt+Shift+Win+F5"
+func (c Combination) String() string {
+	var parts []string
+	if c.Modifiers&ModCtrl != 0 {
+		parts = append(parts, "Ctrl")
+	}
+	if c.Modifiers&ModAlt != 0 {
+		parts = append(parts, "Alt")
+	}
+	if c.Modifiers&ModShift != 0 {
+		parts = append(parts, "Shift")
+	}
+	if c.Modifiers&ModWin != 0 {
+		parts = append(parts, "Win")
+	}
+
+	key := c.Key
+	if len(key) > 1 {
+		// Title case named keys, e.g. PAGEUP -> Pageup, F5 stays F5
+		key = key[:1] + strings.ToLower(key[1:])
+	}
+
+	return strings.Join(append(parts, key), "+")
+}
@@ -0,0 +1,242 @@
+package hotkeys
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var errHotkeyTaken = errors.New("hotkey is already registered")
+
+// fakePlatform records registrations instead of talking to the OS
+type fakePlatform struct {
+	trigger    func(id int)
+	registered map[int]Combination
+	refuse     map[Combination]bool // combinations Register fails for
+	calls      []string
+	stopped    bool
+}
+
+func newFakePlatform() *fakePlatform {
+	return &fakePlatform{registered: make(map[int]Combination), refuse: make(map[Combination]bool)}
+}
+
+func (p *fakePlatform) Start(trigger func(id int)) error {
+	p.trigger = trigger
+	return nil
+}
+
+func (p *fakePlatform) Register(id int, combo Combination) error {
+	p.calls = append(p.calls, fmt.Sprintf("register %d %s", id, combo))
+	if p.refuse[combo] {
+		return errHotkeyTaken
+	}
+	p.registered[id] = combo
+	return nil
+}
+
+func (p *fakePlatform) Unregister(id int) error {
+	p.calls = append(p.calls, fmt.Sprintf("unregister %d", id))
+	delete(p.registered, id)
+	return nil
+}
+
+func (p *fakePlatform) Stop() {
+	p.stopped = true
+}
+
+// press simulates the OS reporting the combination as pressed
+func (p *fakePlatform) press(t *testing.T, combo Combination) {
+	t.Helper()
+	for id, registered := range p.registered {
+		if registered == combo {
+			p.trigger(id)
+			return
+		}
+	}
+	t.Fatalf("%s is not registered", combo)
+}
+
+func mustParse(t *testing.T, text string) Combination {
+	t.Helper()
+	combo, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", text, err)
+	}
+	return combo
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	tests := []struct {
+		text      string
+		canonical string
+		modifiers uint32
+		keyCode   uint32
+	}{
+		{"Ctrl+Alt+1", "Ctrl+Alt+1", ModCtrl | ModAlt, 0x31},
+		{"shift+win+f5", "Shift+Win+F5", ModShift | ModWin, 0x74},
+		{"Win + Shift + Alt + Ctrl + F24", "Ctrl+Alt+Shift+Win+F24", ModCtrl | ModAlt | ModShift | ModWin, 0x87},
+		{"control+pageup", "Ctrl+Pageup", ModCtrl, 0x21},
+		{"Alt+numpad7", "Alt+Numpad7", ModAlt, 0x67},
+		{"Ctrl+z", "Ctrl+Z", ModCtrl, 'Z'},
+	}
+
+	for _, tt := range tests {
+		combo := mustParse(t, tt.text)
+		if combo.Modifiers != tt.modifiers || combo.KeyCode != tt.keyCode {
+			t.Errorf("Parse(%q) = %+v, want modifiers %#x and key code %#x", tt.text, combo, tt.modifiers, tt.keyCode)
+		}
+		if got := combo.String(); got != tt.canonical {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.text, got, tt.canonical)
+		}
+		if again := mustParse(t, combo.String()); again != combo {
+			t.Errorf("Parse(%q) = %+v, want %+v", combo.String(), again, combo)
+		}
+	}
+}
+
+func TestParseRejectsInvalid(t *testing.T) {
+	for _, text := range []string{"", "F5", "Ctrl+", "Hyper+1", "Ctrl+Ctrl+1", "Ctrl+Control+1", "Ctrl+F25", "Ctrl+Alt"} {
+		if combo, err := Parse(text); err == nil {
+			t.Errorf("Parse(%q) = %+v, want an error", text, combo)
+		}
+	}
+}
+
+func TestParseBindingsDuplicate(t *testing.T) {
+	_, err := ParseBindings(map[string]string{
+		"Gaming": "ctrl+alt+1",
+		"Desk":   "Alt+Ctrl+1",
+		"TV":     "Ctrl+Alt+2",
+	})
+
+	var duplicate *DuplicateBindingError
+	if !errors.As(err, &duplicate) {
+		t.Fatalf("ParseBindings() error = %v, want a DuplicateBindingError", err)
+	}
+	if duplicate.Targets != [2]string{"Desk", "Gaming"} {
+		t.Errorf("Targets = %v, want [Desk Gaming]", duplicate.Targets)
+	}
+	if duplicate.Combination.String() != "Ctrl+Alt+1" {
+		t.Errorf("Combination = %s, want Ctrl+Alt+1", duplicate.Combination)
+	}
+}
+
+func TestParseBindings(t *testing.T) {
+	bindings, err := ParseBindings(map[string]string{
+		"TV":     "Ctrl+Alt+2",
+		"Desk":   "Ctrl+Alt+1",
+		"Unused": "",
+	})
+	if err != nil {
+		t.Fatalf("ParseBindings() error = %v", err)
+	}
+
+	var got []string
+	for _, binding := range bindings {
+		got = append(got, binding.Target+"="+binding.Combination.String())
+	}
+	if want := []string{"Desk=Ctrl+Alt+1", "TV=Ctrl+Alt+2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bindings = %v, want %v", got, want)
+	}
+
+	if _, err := ParseBindings(map[string]string{"Desk": "Ctrl+Hyper+1"}); err == nil || !strings.HasPrefix(err.Error(), "Desk: ") {
+		t.Errorf("ParseBindings() error = %v, want an error naming the target", err)
+	}
+}
+
+func TestSetBindingsReregisters(t *testing.T) {
+	platform := newFakePlatform()
+	manager := NewManager(platform, func(string) {})
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	desk := Binding{Combination: mustParse(t, "Ctrl+Alt+1"), Target: "Desk"}
+	tv := Binding{Combination: mustParse(t, "Ctrl+Alt+2"), Target: "TV"}
+
+	if err := manager.SetBindings([]Binding{desk, tv}); err != nil {
+		t.Fatalf("SetBindings() error = %v", err)
+	}
+	if want := map[int]Combination{1: desk.Combination, 2: tv.Combination}; !reflect.DeepEqual(platform.registered, want) {
+		t.Fatalf("registered = %v, want %v", platform.registered, want)
+	}
+
+	// Replacing the bindings unregisters every previous hotkey first
+	platform.calls = nil
+	if err := manager.SetBindings([]Binding{tv}); err != nil {
+		t.Fatalf("SetBindings() error = %v", err)
+	}
+	unregisters := append([]string(nil), platform.calls[:2]...)
+	sort.Strings(unregisters)
+	if want := []string{"unregister 1", "unregister 2"}; !reflect.DeepEqual(unregisters, want) {
+		t.Errorf("calls = %v, want both hotkeys unregistered first", platform.calls)
+	}
+	if want := []string{"register 1 Ctrl+Alt+2"}; !reflect.DeepEqual(platform.calls[2:], want) {
+		t.Errorf("calls = %v, want %v after unregistering", platform.calls, want)
+	}
+
+	manager.Stop()
+	if len(platform.registered) != 0 || !platform.stopped {
+		t.Errorf("after Stop registered = %v, stopped = %v", platform.registered, platform.stopped)
+	}
+}
+
+func TestSetBindingsRegistersRemainingOnFailure(t *testing.T) {
+	platform := newFakePlatform()
+	manager := NewManager(platform, func(string) {})
+
+	desk := Binding{Combination: mustParse(t, "Ctrl+Alt+1"), Target: "Desk"}
+	tv := Binding{Combination: mustParse(t, "Ctrl+Alt+2"), Target: "TV"}
+	platform.refuse[desk.Combination] = true
+
+	err := manager.SetBindings([]Binding{desk, tv})
+	if err == nil || !strings.Contains(err.Error(), `"Desk"`) {
+		t.Errorf("SetBindings() error = %v, want the refused Desk hotkey", err)
+	}
+	if !errors.Is(err, errHotkeyTaken) {
+		t.Errorf("SetBindings() error = %v, want it to wrap the platform error", err)
+	}
+	if want := map[int]Combination{2: tv.Combination}; !reflect.DeepEqual(platform.registered, want) {
+		t.Errorf("registered = %v, want %v", platform.registered, want)
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	platform := newFakePlatform()
+	var dispatched []string
+	manager := NewManager(platform, func(target string) {
+		dispatched = append(dispatched, target)
+	})
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	desk := Binding{Combination: mustParse(t, "Ctrl+Alt+1"), Target: "Desk"}
+	tv := Binding{Combination: mustParse(t, "Ctrl+Alt+2"), Target: "TV"}
+	if err := manager.SetBindings([]Binding{desk, tv}); err != nil {
+		t.Fatal(err)
+	}
+
+	platform.press(t, tv.Combination)
+	platform.press(t, desk.Combination)
+	// IDs that are not bound, e.g. from a previous set of bindings, are ignored
+	manager.Dispatch(99)
+
+	if want := []string{"TV", "Desk"}; !reflect.DeepEqual(dispatched, want) {
+		t.Errorf("dispatched = %v, want %v", dispatched, want)
+	}
+
+	// A pressed hotkey that was rebound dispatches its new target
+	dispatched = nil
+	if err := manager.SetBindings([]Binding{{Combination: tv.Combination, Target: "Couch"}}); err != nil {
+		t.Fatal(err)
+	}
+	platform.press(t, tv.Combination)
+	if want := []string{"Couch"}; !reflect.DeepEqual(dispatched, want) {
+		t.Errorf("dispatched = %v, want %v", dispatched, want)
+	}
+}
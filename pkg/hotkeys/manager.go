@@ -0,0 +1,164 @@
+package hotkeys
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Platform registers global hotkeys with the operating system. Implementations
+// call the trigger function passed to Start with the ID of a pressed hotkey.
+type Platform interface {
+	Start(trigger func(id int)) error
+	Register(id int, combo Combination) error
+	Unregister(id int) error
+	Stop()
+}
+
+// DuplicateBindingError reports two targets bound to the same key combination
+type DuplicateBindingError struct {
+	Combination Combination
+	Targets     [2]string
+}
+
+func (e *DuplicateBindingError) Error() string {
+	return fmt.Sprintf("hotkey %s is assigned to both %q and %q", e.Combination, e.Targets[0], e.Targets[1])
+}
+
+// Binding maps a key combination to a target, e.g. a profile name
+type Binding struct {
+	Combination Combination
+	Target      string
+}
+
+// ParseBindings parses a target -> hotkey text map and rejects invalid hotkeys
+// and combinations bound to more than one target. Empty hotkeys are skipped.
+func ParseBindings(hotkeys map[string]string) ([]Binding, error) {
+	targets := make([]string, 0, len(hotkeys))
+	for target := range hotkeys {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	bindings := make([]Binding, 0, len(hotkeys))
+	byCombination := make(map[Combination]string, len(hotkeys))
+
+	for _, target := range targets {
+		text := hotkeys[target]
+		if text == "" {
+			continue
+		}
+
+		combo, err := Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", target, err)
+		}
+
+		if existing, ok := byCombination[combo]; ok {
+			return nil, &DuplicateBindingError{Combination: combo, Targets: [2]string{existing, target}}
+		}
+		byCombination[combo] = target
+
+		bindings = append(bindings, Binding{Combination: combo, Target: target})
+	}
+
+	return bindings, nil
+}
+
+// Manager keeps the registered hotkeys in sync with a set of bindings and
+// dispatches pressed hotkeys to their target
+type Manager struct {
+	platform Platform
+	dispatch func(target string)
+
+	mu       sync.Mutex
+	started  bool
+	bindings map[int]Binding
+}
+
+// NewManager creates a manager that registers hotkeys through platform and calls
+// dispatch with the target of every pressed hotkey
+func NewManager(platform Platform, dispatch func(target string)) *Manager {
+	return &Manager{
+		platform: platform,
+		dispatch: dispatch,
+		bindings: make(map[int]Binding),
+	}
+}
+
+// Start starts the platform hotkey listener
+func (m *Manager) Start() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.started {
+		return nil
+	}
+
+	if err := m.platform.Start(m.Dispatch); err != nil {
+		return err
+	}
+
+	m.started = true
+	return nil
+}
+
+// Stop unregisters every hotkey and stops the platform listener
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.started {
+		return
+	}
+
+	for id := range m.bindings {
+		m.platform.Unregister(id)
+	}
+	m.bindings = make(map[int]Binding)
+
+	m.platform.Stop()
+	m.started = false
+}
+
+// SetBindings replaces the registered hotkeys. Every binding is attempted, the
+// returned error lists the ones the platform refused (e.g. taken by another app).
+func (m *Manager) SetBindings(bindings []Binding) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []error
+
+	for id := range m.bindings {
+		if err := m.platform.Unregister(id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	m.bindings = make(map[int]Binding)
+
+	for i, binding := range bindings {
+		id := i + 1
+		if err := m.platform.Register(id, binding.Combination); err != nil {
+			errs = append(errs, fmt.Errorf("failed to register hotkey %s for %q: %w", binding.Combination, binding.Target, err))
+			continue
+		}
+		m.bindings[id] = binding
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// Dispatch calls the dispatch function for the binding with the given ID
+func (m *Manager) Dispatch(id int) {
+	m.mu.Lock()
+	binding, ok := m.bindings[id]
+	m.mu.Unlock()
+
+	if ok {
+		m.dispatch(binding.Target)
+	}
+}
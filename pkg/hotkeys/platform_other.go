@@ -0,0 +1,23 @@
+//go:build !windows
+
+package hotkeys
+
+import "fmt"
+
+// unsupportedPlatform is used where global hotkeys are not implemented
+type unsupportedPlatform struct{}
+
+// NewPlatform returns a platform that refuses every registration
+func NewPlatform() Platform {
+	return unsupportedPlatform{}
+}
+
+func (unsupportedPlatform) Start(trigger func(id int)) error { return nil }
+
+func (unsupportedPlatform) Register(id int, combo Combination) error {
+	return fmt.Errorf("global hotkeys are not supported on this platform")
+}
+
+func (unsupportedPlatform) Unregister(id int) error { return nil }
+
+func (unsupportedPlatform) Stop() {}
@@ -0,0 +1,147 @@
+//go:build windows
+
+package hotkeys
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	wmHotkey    = 0x0312
+	wmApp       = 0x8000
+	modNoRepeat = 0x4000
+	pmNoRemove  = 0x0000
+)
+
+var (
+	user32                 = windows.NewLazySystemDLL("user32.dll")
+	procRegisterHotKey     = user32.NewProc("RegisterHotKey")
+	procUnregisterHotKey   = user32.NewProc("UnregisterHotKey")
+	procGetMessageW        = user32.NewProc("GetMessageW")
+	procPeekMessageW       = user32.NewProc("PeekMessageW")
+	procPostThreadMessageW = user32.NewProc("PostThreadMessageW")
+)
+
+type msg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	pt      struct{ x, y int32 }
+}
+
+// request is a register or unregister call run on the message loop thread,
+// RegisterHotKey delivers WM_HOTKEY to the thread that registered it
+type request struct {
+	fn     func() error
+	result chan error
+}
+
+// windowsPlatform registers hotkeys with RegisterHotKey on a dedicated thread
+// running a message loop
+type windowsPlatform struct {
+	threadID uint32
+	requests chan request
+	doneCh   chan struct{}
+}
+
+// NewPlatform returns the Win32 hotkey platform
+func NewPlatform() Platform {
+	return &windowsPlatform{}
+}
+
+func (p *windowsPlatform) Start(trigger func(id int)) error {
+	p.requests = make(chan request, 1)
+	p.doneCh = make(chan struct{})
+
+	ready := make(chan struct{})
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer close(p.doneCh)
+
+		p.threadID = windows.GetCurrentThreadId()
+
+		// A thread has no message queue until it calls a message function,
+		// PostThreadMessageW fails until then so create it before signalling
+		var m msg
+		procPeekMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0, pmNoRemove)
+		close(ready)
+
+		for {
+			ret, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+			if int32(ret) <= 0 {
+				// WM_QUIT or error
+				return
+			}
+
+			switch m.message {
+			case wmHotkey:
+				go trigger(int(m.wParam))
+			case wmApp:
+				select {
+				case req := <-p.requests:
+					req.result <- req.fn()
+				default:
+				}
+			}
+		}
+	}()
+
+	<-ready
+	return nil
+}
+
+// call runs fn on the message loop thread and waits for its result
+func (p *windowsPlatform) call(fn func() error) error {
+	if p.requests == nil {
+		return fmt.Errorf("hotkey platform is not started")
+	}
+
+	req := request{fn: fn, result: make(chan error, 1)}
+	p.requests <- req
+
+	ret, _, err := procPostThreadMessageW.Call(uintptr(p.threadID), wmApp, 0, 0)
+	if ret == 0 {
+		<-p.requests
+		return fmt.Errorf("failed to reach hotkey thread: %v", err)
+	}
+
+	return <-req.result
+}
+
+func (p *windowsPlatform) Register(id int, combo Combination) error {
+	return p.call(func() error {
+		ret, _, err := procRegisterHotKey.Call(0, uintptr(id), uintptr(combo.Modifiers|modNoRepeat), uintptr(combo.KeyCode))
+		if ret == 0 {
+			return fmt.Errorf("RegisterHotKey failed: %v", err)
+		}
+		return nil
+	})
+}
+
+func (p *windowsPlatform) Unregister(id int) error {
+	return p.call(func() error {
+		ret, _, err := procUnregisterHotKey.Call(0, uintptr(id))
+		if ret == 0 {
+			return fmt.Errorf("UnregisterHotKey failed: %v", err)
+		}
+		return nil
+	})
+}
+
+func (p *windowsPlatform) Stop() {
+	if p.requests == nil {
+		return
+	}
+
+	// WM_QUIT ends GetMessageW
+	procPostThreadMessageW.Call(uintptr(p.threadID), 0x0012, 0, 0)
+	<-p.doneCh
+	p.requests = nil
+}
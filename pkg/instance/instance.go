@@ -0,0 +1,134 @@
+package instance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+var (
+	// ErrAlreadyRunning is returned by Listen when another instance owns the address
+	ErrAlreadyRunning = errors.New("another instance is already running")
+	// ErrNotRunning is returned by Forward when no instance is listening
+	ErrNotRunning = errors.New("no running instance")
+	errClosed     = errors.New("listener closed")
+)
+
+// Listener accepts connections on the platform IPC channel, a named pipe on
+// Windows and a Unix socket elsewhere
+type Listener interface {
+	Accept() (io.ReadWriteCloser, error)
+	Close() error
+}
+
+// request is sent by a second launch with its command line arguments
+type request struct {
+	Args []string `json:"args"`
+}
+
+// response reports whether the running instance handled the arguments
+type response struct {
+	Error string `json:"error,omitempty"`
+}
+
+// Server receives the arguments of later launches
+type Server struct {
+	listener Listener
+	handle   func(args []string) error
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Listen claims the address for this instance. ErrAlreadyRunning is returned
+// when another instance already listens on it.
+func Listen(address string, handle func(args []string) error) (*Server, error) {
+	listener, err := listen(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{listener: listener, handle: handle}, nil
+}
+
+// Serve handles forwarded arguments until Close is called
+func (s *Server) Serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return
+			}
+
+			slog.Error("Instance server failed", "error", err)
+			continue
+		}
+
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn io.ReadWriteCloser) {
+	defer conn.Close()
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		slog.Warn("Instance server: invalid request", "error", err)
+		return
+	}
+
+	var resp response
+	if err := s.handle(req.Args); err != nil {
+		resp.Error = err.Error()
+	}
+
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		slog.Warn("Instance server: failed to reply", "error", err)
+	}
+}
+
+// Close stops accepting connections and releases the address
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	return s.listener.Close()
+}
+
+// Forward sends command line arguments to the running instance and waits for
+// it to handle them. ErrNotRunning is returned when no instance is listening.
+func Forward(address string, args []string) error {
+	conn, err := dial(address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if args == nil {
+		args = []string{}
+	}
+	if err := json.NewEncoder(conn).Encode(request{Args: args}); err != nil {
+		return fmt.Errorf("failed to forward arguments: %w", err)
+	}
+
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return fmt.Errorf("failed to read reply from running instance: %w", err)
+	}
+
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+//go:build !windows
+
+package instance
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// Address returns the Unix socket path for name inside dir
+func Address(name string, dir string) string {
+	return filepath.Join(dir, name+".sock")
+}
+
+type unixListener struct {
+	listener net.Listener
+}
+
+func listen(address string) (Listener, error) {
+	listener, err := net.Listen("unix", address)
+	if err != nil {
+		// A socket file is left behind when an instance crashes, only give up
+		// when something still answers on it
+		if conn, dialErr := net.Dial("unix", address); dialErr == nil {
+			conn.Close()
+			return nil, ErrAlreadyRunning
+		}
+
+		os.Remove(address)
+		if listener, err = net.Listen("unix", address); err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+		}
+	}
+
+	return &unixListener{listener: listener}, nil
+}
+
+func (l *unixListener) Accept() (io.ReadWriteCloser, error) {
+	return l.listener.Accept()
+}
+
+func (l *unixListener) Close() error {
+	return l.listener.Close()
+}
+
+func dial(address string) (io.ReadWriteCloser, error) {
+	conn, err := net.Dial("unix", address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	return conn, nil
+}
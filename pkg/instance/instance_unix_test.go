@@ -0,0 +1,137 @@
+//go:build !windows
+
+package instance
+
+import (
+	"errors"
+	"net"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// serve listens on a new address and serves until the test ends
+func serve(t *testing.T, handle func(args []string) error) string {
+	t.Helper()
+
+	address := Address("test", t.TempDir())
+	server, err := Listen(address, handle)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go server.Serve()
+	t.Cleanup(func() { server.Close() })
+
+	return address
+}
+
+func TestForwardDeliversArgs(t *testing.T) {
+	received := make(chan []string, 1)
+	address := serve(t, func(args []string) error {
+		received <- args
+		return nil
+	})
+
+	args := []string{"--apply", "Desk"}
+	if err := Forward(address, args); err != nil {
+		t.Fatalf("Forward() error = %v", err)
+	}
+	if got := <-received; !reflect.DeepEqual(got, args) {
+		t.Errorf("handled args = %v, want %v", got, args)
+	}
+
+	// No arguments arrive as an empty list
+	if err := Forward(address, nil); err != nil {
+		t.Fatalf("Forward(nil) error = %v", err)
+	}
+	if got := <-received; got == nil || len(got) != 0 {
+		t.Errorf("handled args = %#v, want empty", got)
+	}
+}
+
+func TestForwardReturnsHandlerError(t *testing.T) {
+	address := serve(t, func(args []string) error {
+		return errors.New("profile not found: Desk")
+	})
+
+	err := Forward(address, []string{"--apply", "Desk"})
+	if err == nil || err.Error() != "profile not found: Desk" {
+		t.Errorf("Forward() error = %v, want the handler error", err)
+	}
+}
+
+func TestForwardWithoutInstance(t *testing.T) {
+	err := Forward(Address("test", t.TempDir()), nil)
+	if !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Forward() error = %v, want ErrNotRunning", err)
+	}
+}
+
+func TestListenAlreadyRunning(t *testing.T) {
+	address := serve(t, func(args []string) error { return nil })
+
+	if _, err := Listen(address, func(args []string) error { return nil }); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("second Listen() error = %v, want ErrAlreadyRunning", err)
+	}
+}
+
+func TestListenReplacesStaleSocket(t *testing.T) {
+	address := Address("test", t.TempDir())
+
+	// A crashed instance leaves its socket file behind
+	listener, err := net.Listen("unix", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	listener.Close()
+	if _, err := os.Stat(address); err != nil {
+		t.Fatalf("stale socket missing: %v", err)
+	}
+
+	received := make(chan []string, 1)
+	server, err := Listen(address, func(args []string) error {
+		received <- args
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Listen() over a stale socket error = %v", err)
+	}
+	go server.Serve()
+	defer server.Close()
+
+	if err := Forward(address, []string{"--minimized"}); err != nil {
+		t.Fatalf("Forward() error = %v", err)
+	}
+	if got := <-received; !reflect.DeepEqual(got, []string{"--minimized"}) {
+		t.Errorf("handled args = %v", got)
+	}
+}
+
+func TestCloseReleasesAddress(t *testing.T) {
+	address := Address("test", t.TempDir())
+	server, err := Listen(address, func(args []string) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		server.Serve()
+		close(done)
+	}()
+
+	if err := server.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	<-done
+
+	if err := Forward(address, nil); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Forward() after Close error = %v, want ErrNotRunning", err)
+	}
+	next, err := Listen(address, func(args []string) error { return nil })
+	if err != nil {
+		t.Fatalf("Listen() after Close error = %v", err)
+	}
+	next.Close()
+}
@@ -0,0 +1,178 @@
+//go:build windows
+
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	pipeBufferSize    = 4096
+	pipeBusyRetries   = 20
+	pipeBusyRetryWait = 50 * time.Millisecond
+)
+
+// Address returns the named pipe path for name. Pipes live in a machine wide
+// namespace so the user name is added to keep sessions apart, dir is unused.
+func Address(name string, dir string) string {
+	return `\\.\pipe\` + name + "-" + os.Getenv("USERNAME")
+}
+
+// pipeListener serves a named pipe, creating a new pipe instance for every
+// accepted connection
+type pipeListener struct {
+	address string
+
+	mu        sync.Mutex
+	next      windows.Handle
+	accepting bool
+	closed    bool
+}
+
+func createPipe(address string, first bool) (windows.Handle, error) {
+	name, err := windows.UTF16PtrFromString(address)
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+
+	flags := uint32(windows.PIPE_ACCESS_DUPLEX)
+	if first {
+		flags |= windows.FILE_FLAG_FIRST_PIPE_INSTANCE
+	}
+
+	return windows.CreateNamedPipe(
+		name,
+		flags,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+		windows.PIPE_UNLIMITED_INSTANCES,
+		pipeBufferSize,
+		pipeBufferSize,
+		0,
+		nil,
+	)
+}
+
+func listen(address string) (Listener, error) {
+	// FILE_FLAG_FIRST_PIPE_INSTANCE fails when another process created the pipe
+	handle, err := createPipe(address, true)
+	if errors.Is(err, windows.ERROR_ACCESS_DENIED) {
+		return nil, ErrAlreadyRunning
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipe %s: %w", address, err)
+	}
+
+	return &pipeListener{address: address, next: handle}, nil
+}
+
+func (l *pipeListener) Accept() (io.ReadWriteCloser, error) {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, errClosed
+	}
+	if l.next == windows.InvalidHandle {
+		next, err := createPipe(l.address, false)
+		if err != nil {
+			l.mu.Unlock()
+			return nil, fmt.Errorf("failed to create pipe %s: %w", l.address, err)
+		}
+		l.next = next
+	}
+	handle := l.next
+	l.accepting = true
+	l.mu.Unlock()
+
+	err := windows.ConnectNamedPipe(handle, nil)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.accepting = false
+	l.next = windows.InvalidHandle
+
+	if l.closed {
+		windows.CloseHandle(handle)
+		return nil, errClosed
+	}
+	if err != nil && !errors.Is(err, windows.ERROR_PIPE_CONNECTED) {
+		windows.CloseHandle(handle)
+		return nil, fmt.Errorf("failed to accept pipe connection: %w", err)
+	}
+
+	// Create the next instance right away so the pipe name never disappears
+	// while a connection is being served
+	if next, err := createPipe(l.address, false); err == nil {
+		l.next = next
+	}
+
+	return &pipeConn{File: os.NewFile(uintptr(handle), l.address), handle: handle}, nil
+}
+
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	l.closed = true
+	accepting := l.accepting
+	handle := l.next
+	l.mu.Unlock()
+
+	// ConnectNamedPipe blocks, connecting to the pipe wakes it up so Accept
+	// sees the listener is closed and releases the handle
+	if accepting {
+		if conn, err := dial(l.address); err == nil {
+			conn.Close()
+		}
+		return nil
+	}
+
+	if handle != windows.InvalidHandle {
+		return windows.CloseHandle(handle)
+	}
+	return nil
+}
+
+// pipeConn is the server end of a pipe connection
+type pipeConn struct {
+	*os.File
+	handle windows.Handle
+}
+
+// Close waits for the client to read the reply before disconnecting, closing
+// the handle straight away would discard unread data
+func (c *pipeConn) Close() error {
+	windows.FlushFileBuffers(c.handle)
+	windows.DisconnectNamedPipe(c.handle)
+	return c.File.Close()
+}
+
+func dial(address string) (io.ReadWriteCloser, error) {
+	name, err := windows.UTF16PtrFromString(address)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		handle, err := windows.CreateFile(name, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING, 0, 0)
+		if err == nil {
+			return os.NewFile(uintptr(handle), address), nil
+		}
+
+		// Every pipe instance is in use until the server creates the next one
+		if errors.Is(err, windows.ERROR_PIPE_BUSY) && attempt < pipeBusyRetries {
+			time.Sleep(pipeBusyRetryWait)
+			continue
+		}
+
+		if errors.Is(err, windows.ERROR_FILE_NOT_FOUND) {
+			return nil, fmt.Errorf("%w: %v", ErrNotRunning, err)
+		}
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+}
@@ -0,0 +1,219 @@
+package integrity
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tool states
+const (
+	StateVerified = "verified" // the file matches the expected hash
+	StateRestored = "restored" // the file did not match and was restored
+	StateRefused  = "refused"  // the file does not match and is not run
+)
+
+// Checker is consulted before a tool is run
+type Checker interface {
+	Check(path string) error
+	Refused(path string) bool
+}
+
+// Tool is a file whose content must match Expected, a hex encoded SHA-256
+type Tool struct {
+	Name     string
+	Path     string
+	Expected string
+	Restore  func() error // rewrites the file from a trusted copy, may be nil
+}
+
+// Status is the last verification result of a tool
+type Status struct {
+	Tool     string    `json:"tool"`
+	Path     string    `json:"path"`
+	Expected string    `json:"expected"`
+	Actual   string    `json:"actual,omitempty"`
+	State    string    `json:"state"`
+	Error    string    `json:"error,omitempty"`
+	Checked  time.Time `json:"checked"`
+}
+
+// Verifier checks registered tools before they are run. A tool that does not
+// match is restored once, when that fails it is refused until it is added again.
+type Verifier struct {
+	mu       sync.Mutex
+	tools    map[string]Tool
+	statuses map[string]Status
+	onChange func(Status)
+}
+
+// NewVerifier creates a verifier. onChange is called whenever the state of a
+// tool changes and may be nil.
+func NewVerifier(onChange func(Status)) *Verifier {
+	return &Verifier{
+		tools:    make(map[string]Tool),
+		statuses: make(map[string]Status),
+		onChange: onChange,
+	}
+}
+
+// HashFile returns the hex encoded SHA-256 of a file
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// HashBytes returns the hex encoded SHA-256 of data
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseChecksums parses sha256sum output, "<hash>  <path>" per line, into a
+// map from slash separated path to lower case hash
+func ParseChecksums(data []byte) (map[string]string, error) {
+	checksums := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, path, ok := strings.Cut(text, " ")
+		path = strings.TrimPrefix(strings.TrimSpace(path), "*")
+		if !ok || len(hash) != sha256.Size*2 || path == "" {
+			return nil, fmt.Errorf("invalid checksum on line %d", line)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("invalid checksum on line %d", line)
+		}
+		checksums[filepath.ToSlash(path)] = strings.ToLower(hash)
+	}
+
+	return checksums, scanner.Err()
+}
+
+// key normalizes a tool path for lookups
+func key(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return strings.ToLower(filepath.Clean(path))
+}
+
+// Add registers a tool and verifies it
+func (v *Verifier) Add(tool Tool) error {
+	v.mu.Lock()
+	v.tools[key(tool.Path)] = tool
+	delete(v.statuses, key(tool.Path))
+	v.mu.Unlock()
+
+	return v.Check(tool.Path)
+}
+
+// Refuse registers a tool that must not run, e.g. because its trusted copy
+// is already wrong
+func (v *Verifier) Refuse(tool Tool, reason error) {
+	v.mu.Lock()
+	v.tools[key(tool.Path)] = tool
+	status := Status{Tool: tool.Name, Path: tool.Path, Expected: tool.Expected, State: StateRefused, Error: reason.Error(), Checked: time.Now()}
+	v.statuses[key(tool.Path)] = status
+	v.mu.Unlock()
+
+	if v.onChange != nil {
+		v.onChange(status)
+	}
+}
+
+// Check verifies a tool before it is run. Paths that were never added are
+// not checked.
+func (v *Verifier) Check(path string) error {
+	v.mu.Lock()
+	tool, ok := v.tools[key(path)]
+	previous, checked := v.statuses[key(path)]
+	v.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	if checked && previous.State == StateRefused {
+		return fmt.Errorf("%s is refused: %s", tool.Name, previous.Error)
+	}
+
+	status := Status{Tool: tool.Name, Path: tool.Path, Expected: tool.Expected, State: StateVerified, Checked: time.Now()}
+	actual, err := HashFile(tool.Path)
+	status.Actual = actual
+
+	if err != nil || actual != tool.Expected {
+		mismatch := fmt.Errorf("%s does not match its recorded SHA-256 (expected %s, got %s)", tool.Name, tool.Expected, actual)
+		if err != nil {
+			mismatch = fmt.Errorf("failed to verify %s: %w", tool.Name, err)
+		}
+
+		status.State = StateRefused
+		status.Error = mismatch.Error()
+		if tool.Restore != nil {
+			if restoreErr := tool.Restore(); restoreErr != nil {
+				status.Error = fmt.Sprintf("%v, restoring failed: %v", mismatch, restoreErr)
+			} else if restored, err := HashFile(tool.Path); err == nil && restored == tool.Expected {
+				status.State = StateRestored
+				status.Actual = restored
+			}
+		}
+	}
+
+	v.mu.Lock()
+	v.statuses[key(path)] = status
+	v.mu.Unlock()
+
+	if v.onChange != nil && (!checked || previous.State != status.State) {
+		v.onChange(status)
+	}
+
+	if status.State == StateRefused {
+		return fmt.Errorf("refusing to run %s: %s", tool.Name, status.Error)
+	}
+	return nil
+}
+
+// Refused reports whether a tool was refused and will not be run
+func (v *Verifier) Refused(path string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	status, ok := v.statuses[key(path)]
+	return ok && status.State == StateRefused
+}
+
+// Statuses returns the last verification result of every tool, ordered by name
+func (v *Verifier) Statuses() []Status {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	statuses := make([]Status, 0, len(v.statuses))
+	for _, status := range v.statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Tool < statuses[j].Tool })
+	return statuses
+}
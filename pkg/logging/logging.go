@@ -0,0 +1,215 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a log record kept in memory for the UI
+type Entry struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+}
+
+// Options configures a Logger
+type Options struct {
+	Path      string    // log file, empty logs to memory and Console only
+	MaxSize   int64     // bytes written to a file before it is rotated
+	MaxFiles  int       // rotated files kept next to the current one
+	MaxRecent int       // entries kept in memory for Recent
+	Console   io.Writer // optional human readable copy of every record, e.g. os.Stderr
+}
+
+// Logger writes JSON lines to a rotating file and keeps the most recent
+// entries in memory. The level can be changed while it is in use.
+type Logger struct {
+	level  slog.LevelVar
+	file   *RotatingFile
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	recent    []Entry
+	maxRecent int
+}
+
+// New creates a logger. When the log file cannot be opened the logger is
+// still usable and the error is returned alongside it.
+func New(options Options) (*Logger, error) {
+	l := &Logger{maxRecent: options.MaxRecent}
+
+	var handlers []slog.Handler
+	var err error
+	if options.Path != "" {
+		l.file, err = OpenRotatingFile(options.Path, options.MaxSize, options.MaxFiles)
+		if err == nil {
+			handlers = append(handlers, slog.NewJSONHandler(l.file, &slog.HandlerOptions{Level: &l.level}))
+		}
+	}
+	if options.Console != nil {
+		handlers = append(handlers, slog.NewTextHandler(options.Console, &slog.HandlerOptions{Level: &l.level}))
+	}
+
+	l.logger = slog.New(&handler{logger: l, handlers: handlers})
+	return l, err
+}
+
+// Slog returns the structured logger
+func (l *Logger) Slog() *slog.Logger {
+	return l.logger
+}
+
+// Level returns the minimum level that is logged
+func (l *Logger) Level() slog.Level {
+	return l.level.Level()
+}
+
+// SetLevel changes the minimum level that is logged
+func (l *Logger) SetLevel(level slog.Level) {
+	l.level.Set(level)
+}
+
+// Files returns the log files on disk, newest first
+func (l *Logger) Files() []string {
+	if l.file == nil {
+		return []string{}
+	}
+	return l.file.Files()
+}
+
+// Recent returns up to limit of the most recent entries at or above
+// minLevel, oldest first. A limit of 0 returns every kept entry.
+func (l *Logger) Recent(minLevel slog.Level, limit int) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := []Entry{}
+	for i := len(l.recent) - 1; i >= 0; i-- {
+		level, _ := ParseLevel(l.recent[i].Level)
+		if level < minLevel {
+			continue
+		}
+		entries = append(entries, l.recent[i])
+		if limit > 0 && len(entries) == limit {
+			break
+		}
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries
+}
+
+// Close closes the log file
+func (l *Logger) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// remember keeps an entry in memory, dropping the oldest
+func (l *Logger) remember(entry Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.recent = append(l.recent, entry)
+	if len(l.recent) > l.maxRecent {
+		l.recent = l.recent[len(l.recent)-l.maxRecent:]
+	}
+}
+
+// ParseLevel parses "debug", "info", "warn" or "error", case insensitive
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level: %s", name)
+	}
+	return level, nil
+}
+
+// handler fans records out to the file and console handlers and keeps them in
+// memory. Attributes and groups are flattened to "group.key" in memory.
+type handler struct {
+	logger   *Logger
+	handlers []slog.Handler
+	attrs    []slog.Attr
+	group    string
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.logger.level.Level()
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	entry := Entry{
+		Time:    record.Time,
+		Level:   record.Level.String(),
+		Message: record.Message,
+	}
+
+	if len(h.attrs) > 0 || record.NumAttrs() > 0 {
+		entry.Attrs = make(map[string]string, len(h.attrs)+record.NumAttrs())
+	}
+	for _, attr := range h.attrs {
+		entry.Attrs[attr.Key] = attr.Value.Resolve().String()
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		key := attr.Key
+		if h.group != "" {
+			key = h.group + "." + key
+		}
+		entry.Attrs[key] = attr.Value.Resolve().String()
+		return true
+	})
+	h.logger.remember(entry)
+
+	for _, inner := range h.handlers {
+		if inner.Enabled(ctx, record.Level) {
+			inner.Handle(ctx, record.Clone())
+		}
+	}
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := h.clone()
+	for i, inner := range h.handlers {
+		next.handlers[i] = inner.WithAttrs(attrs)
+	}
+	for _, attr := range attrs {
+		if h.group != "" {
+			attr.Key = h.group + "." + attr.Key
+		}
+		next.attrs = append(next.attrs, attr)
+	}
+	return next
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	next := h.clone()
+	for i, inner := range h.handlers {
+		next.handlers[i] = inner.WithGroup(name)
+	}
+	if h.group != "" {
+		name = h.group + "." + name
+	}
+	next.group = name
+	return next
+}
+
+func (h *handler) clone() *handler {
+	return &handler{
+		logger:   h.logger,
+		handlers: append([]slog.Handler{}, h.handlers...),
+		attrs:    append([]slog.Attr{}, h.attrs...),
+		group:    h.group,
+	}
+}
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// RotatingFile is an append-only log file that is rotated once it grows past
+// maxSize. Rotated files are renamed app.1.log, app.2.log and so on, the
+// oldest beyond maxFiles are deleted.
+type RotatingFile struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// OpenRotatingFile opens or creates the log file at path
+func OpenRotatingFile(path string, maxSize int64, maxFiles int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	r := &RotatingFile{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the current log file for appending
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write appends p to the log file, rotating first when p does not fit
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		// A failed rotation keeps appending to the current file
+		if err := r.rotate(); err != nil && r.file == nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotatedPath returns the path of the n-th rotated file, e.g. app.1.log
+func (r *RotatingFile) rotatedPath(n int) string {
+	ext := filepath.Ext(r.path)
+	return fmt.Sprintf("%s.%d%s", strings.TrimSuffix(r.path, ext), n, ext)
+}
+
+// rotate shifts the rotated files up by one and starts a new log file. The
+// current file is reopened when it cannot be renamed.
+func (r *RotatingFile) rotate() error {
+	r.file.Close()
+	r.file = nil
+
+	os.Remove(r.rotatedPath(r.maxFiles))
+	for n := r.maxFiles - 1; n >= 1; n-- {
+		os.Rename(r.rotatedPath(n), r.rotatedPath(n+1))
+	}
+	renameErr := os.Rename(r.path, r.rotatedPath(1))
+
+	if err := r.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return fmt.Errorf("failed to rotate log file: %w", renameErr)
+	}
+	return nil
+}
+
+// Files returns the current and rotated log files that exist, newest first
+func (r *RotatingFile) Files() []string {
+	files := []string{}
+	for n := 0; n <= r.maxFiles; n++ {
+		path := r.path
+		if n > 0 {
+			path = r.rotatedPath(n)
+		}
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// Close closes the log file, later writes fail
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
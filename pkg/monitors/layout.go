@@ -0,0 +1,125 @@
+package monitors
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Layout column name constants, these columns are optional
+const (
+	ColResolution = "Resolution"
+	ColLeftTop    = "Left-Top"
+	ColFrequency  = "Frequency"
+)
+
+// Layout is the resolution, position and refresh rate of an active monitor
+type Layout struct {
+	Width     int
+	Height    int
+	X         int
+	Y         int
+	Frequency int // refresh rate in Hz, 0 when unknown
+}
+
+func (l Layout) String() string {
+	s := fmt.Sprintf("%dx%d at %d,%d", l.Width, l.Height, l.X, l.Y)
+	if l.Frequency > 0 {
+		s += fmt.Sprintf(" %dHz", l.Frequency)
+	}
+	return s
+}
+
+// Matches reports whether two layouts are the same. The refresh rate is only
+// compared when both are known.
+func (l Layout) Matches(other Layout) bool {
+	if l.Width != other.Width || l.Height != other.Height || l.X != other.X || l.Y != other.Y {
+		return false
+	}
+	return l.Frequency == 0 || other.Frequency == 0 || l.Frequency == other.Frequency
+}
+
+// GetLayout parses the resolution ("1920 X 1080"), position ("0, 0") and
+// frequency columns. ok is false when the monitor has no layout, e.g. when it
+// is not active.
+func (m MonitorInfo) GetLayout() (layout Layout, ok bool) {
+	if _, err := fmt.Sscanf(m.data[ColResolution], "%d X %d", &layout.Width, &layout.Height); err != nil {
+		return Layout{}, false
+	}
+	if _, err := fmt.Sscanf(m.data[ColLeftTop], "%d, %d", &layout.X, &layout.Y); err != nil {
+		return Layout{}, false
+	}
+	layout.Frequency, _ = strconv.Atoi(m.data[ColFrequency])
+
+	return layout, layout.Width > 0 && layout.Height > 0
+}
+
+// ReadConfigLayouts reads the layouts of the active monitors from a
+// configuration written by SaveMonitorConfig, keyed by device name
+func ReadConfigLayouts(configPath string) (map[string]Layout, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read monitor configuration: %w", err)
+	}
+
+	layouts := make(map[string]Layout)
+	name := ""
+	var layout Layout
+	flush := func() {
+		if name != "" && layout.Width > 0 && layout.Height > 0 {
+			layouts[name] = layout
+		}
+		name, layout = "", Layout{}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(decodeConfig(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			flush()
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		number, _ := strconv.Atoi(strings.TrimSpace(value))
+
+		switch strings.TrimSpace(key) {
+		case "Name":
+			name = strings.TrimSpace(value)
+		case "Width":
+			layout.Width = number
+		case "Height":
+			layout.Height = number
+		case "PositionX":
+			layout.X = number
+		case "PositionY":
+			layout.Y = number
+		case "DisplayFrequency":
+			layout.Frequency = number
+		}
+	}
+	flush()
+
+	return layouts, scanner.Err()
+}
+
+// decodeConfig returns the configuration as UTF-8, converting it when it was
+// saved as UTF-16 with a byte order mark
+func decodeConfig(data []byte) []byte {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xFE {
+		return data
+	}
+
+	units := make([]uint16, 0, len(data)/2)
+	for i := 2; i+1 < len(data); i += 2 {
+		units = append(units, uint16(data[i])|uint16(data[i+1])<<8)
+	}
+	return []byte(string(utf16.Decode(units)))
+}
@@ -3,13 +3,14 @@ package monitors
 import (
 	"encoding/csv"
 	"fmt"
+	"log/slog"
+	"monitor-profile-manager-wails/pkg/audit"
 	"monitor-profile-manager-wails/pkg/common"
+	"monitor-profile-manager-wails/pkg/integrity"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strings"
-	"syscall"
 	"time"
 )
 
@@ -27,11 +28,34 @@ const (
 // MonitorTools manages monitor operations with configurable tools directory
 type MonitorTools struct {
 	toolsDir string
+	recorder audit.Recorder       // records every tool invocation, may be nil
+	checker  integrity.Checker    // verifies the tool before every run, may be nil
+	override *common.ToolOverride // MultiMonitorTool.exe configured in the settings, shared with WithOrigin
 }
 
-// NewMonitorTools creates a new MonitorTools instance with the specified tools directory
-func NewMonitorTools(toolsDir string) *MonitorTools {
-	return &MonitorTools{toolsDir: toolsDir}
+// NewMonitorTools creates a new MonitorTools instance with the specified tools directory.
+// Every tool invocation is passed to recorder and checker is consulted before
+// every run.
+func NewMonitorTools(toolsDir string, recorder audit.Recorder, checker integrity.Checker) *MonitorTools {
+	return &MonitorTools{toolsDir: toolsDir, recorder: recorder, checker: checker, override: &common.ToolOverride{}}
+}
+
+// WithOrigin returns tools that share the configured path with m and
+// record every invocation with the given origin, e.g. audit.OriginPoll
+func (m *MonitorTools) WithOrigin(origin string) *MonitorTools {
+	tools := *m
+	tools.recorder = audit.WithOrigin(m.recorder, origin)
+	return &tools
+}
+
+// run verifies the tool and runs the command, recording the invocation
+func (m *MonitorTools) run(cmd *exec.Cmd) ([]byte, error) {
+	if m.checker != nil {
+		if err := m.checker.Check(cmd.Path); err != nil {
+			return nil, err
+		}
+	}
+	return audit.Run(m.recorder, cmd)
 }
 
 // MonitorInfo represents information about a monitor
@@ -55,20 +79,6 @@ func evaluateValueToBoolean(flag string) bool {
 	return flag == "Yes"
 }
 
-// hideConsoleCommand creates a command with hidden console window on Windows
-func (m *MonitorTools) hideConsoleCommand(name string, args ...string) *exec.Cmd {
-	cmd := exec.Command(name, args...)
-
-	// Hide console window on Windows
-	if runtime.GOOS == "windows" {
-		cmd.SysProcAttr = &syscall.SysProcAttr{
-			HideWindow: true,
-		}
-	}
-
-	return cmd
-}
-
 // GetExecutableDir returns the directory where the executable is running
 func GetExecutableDir() (string, error) {
 	exe, err := os.Executable()
@@ -80,18 +90,26 @@ func GetExecutableDir() (string, error) {
 
 // GetMultiMonitorToolPath returns the full path to MultiMonitorTool.exe
 func (m *MonitorTools) GetMultiMonitorToolPath() (string, error) {
-	// If custom tools directory is set (embedded tools), use it
-	if m.toolsDir != "" {
-		return filepath.Join(m.toolsDir, "multimonitortool", MultiMonitorToolExe), nil
-	}
+	path, _, err := m.ResolveMultiMonitorToolPath()
+	return path, err
+}
+
+// ResolveMultiMonitorToolPath returns the path of MultiMonitorTool.exe and
+// where it was found, one of the common.ToolSource constants
+func (m *MonitorTools) ResolveMultiMonitorToolPath() (string, string, error) {
+	override := m.override.Get()
 
-	// Check if we're in development mode (wails dev)
-	if common.IsDevelopmentMode() {
-		// Development mode: use relative path from project root
-		return filepath.Join("tools", "multimonitortool", MultiMonitorToolExe), nil
+	var refused func(string) bool
+	if m.checker != nil {
+		refused = m.checker.Refused
 	}
+	return common.ResolveTool(MultiMonitorToolExe, override, m.toolsDir, "multimonitortool", refused)
+}
 
-	return "", fmt.Errorf("MultiMonitorTool.exe not found in current directory")
+// SetMultiMonitorToolPath sets a MultiMonitorTool.exe that is used instead of
+// the embedded one, an empty path removes the override
+func (m *MonitorTools) SetMultiMonitorToolPath(path string) {
+	m.override.Set(path)
 }
 
 // CheckMultiMonitorToolExists verifies that MultiMonitorTool.exe exists
@@ -102,7 +120,7 @@ func (m *MonitorTools) CheckMultiMonitorToolExists() error {
 	}
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return fmt.Errorf("MultiMonitorTool.exe not found in current directory")
+		return fmt.Errorf("MultiMonitorTool.exe not found at %s", path)
 	}
 	return nil
 }
@@ -122,7 +140,7 @@ func (m *MonitorTools) GetMonitorList() ([]MonitorInfo, error) {
 
 	// Export monitor list to CSV
 	cmd := m.hideConsoleCommand(toolPath, "/List", "/scomma", "monitors.csv")
-	err = cmd.Run()
+	_, err = m.run(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute MultiMonitorTool: %w", err)
 	}
@@ -179,6 +197,12 @@ func (m *MonitorTools) GetMonitorList() ([]MonitorInfo, error) {
 		}
 
 		if validRow {
+			// Layout columns are optional
+			for _, colName := range []string{ColResolution, ColLeftTop, ColFrequency} {
+				if idx, exists := colIndexes[colName]; exists && idx < len(row) {
+					monitorData[colName] = strings.TrimSpace(row[idx])
+				}
+			}
 			monitors = append(monitors, MonitorInfo{data: monitorData})
 		}
 	}
@@ -194,13 +218,43 @@ func (m *MonitorTools) GetMonitorList() ([]MonitorInfo, error) {
 			time.Sleep(100 * time.Millisecond)
 		} else {
 			// Log warning but don't fail the operation
-			fmt.Printf("Warning: failed to remove monitors.csv after retries: %v\n", err)
+			slog.Warn("Failed to remove monitors.csv after retries", "error", err)
 		}
 	}
 
 	return monitors, nil
 }
 
+// GetMonitorListCSV returns the raw CSV written by MultiMonitorTool /List /scomma
+func (m *MonitorTools) GetMonitorListCSV() ([]byte, error) {
+	// Check if MultiMonitorTool.exe exists
+	if err := m.CheckMultiMonitorToolExists(); err != nil {
+		return nil, err
+	}
+
+	// Get MultiMonitorTool path
+	toolPath, err := m.GetMultiMonitorToolPath()
+	if err != nil {
+		return nil, err
+	}
+
+	// Export to a private directory, GetMonitorList owns monitors.csv in the
+	// working directory
+	tempDir, err := os.MkdirTemp("", "monitor-list")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	csvPath := filepath.Join(tempDir, "monitors.csv")
+	cmd := m.hideConsoleCommand(toolPath, "/List", "/scomma", csvPath)
+	if _, err := m.run(cmd); err != nil {
+		return nil, fmt.Errorf("failed to execute MultiMonitorTool: %w", err)
+	}
+
+	return os.ReadFile(csvPath)
+}
+
 // SaveMonitorConfig saves the current monitor configuration to a file
 func (m *MonitorTools) SaveMonitorConfig(configPath string) error {
 	// Check if MultiMonitorTool.exe exists
@@ -216,7 +270,7 @@ func (m *MonitorTools) SaveMonitorConfig(configPath string) error {
 
 	// Execute the save config command
 	cmd := m.hideConsoleCommand(toolPath, "/SaveConfig", configPath)
-	err = cmd.Run()
+	_, err = m.run(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to save monitor configuration: %w", err)
 	}
@@ -237,7 +291,7 @@ func (m *MonitorTools) DisableMonitor(monitorId string) error {
 	}
 
 	cmd := m.hideConsoleCommand(toolPath, "/disable", monitorId)
-	err = cmd.Run()
+	_, err = m.run(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to disable monitor: %w", err)
 	}
@@ -258,7 +312,7 @@ func (m *MonitorTools) EnableMonitor(monitorId string) error {
 	}
 
 	cmd := m.hideConsoleCommand(toolPath, "/enable", monitorId)
-	err = cmd.Run()
+	_, err = m.run(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to enable monitor: %w", err)
 	}
@@ -279,7 +333,7 @@ func (m *MonitorTools) SetMonitorAsPrimary(monitorId string) error {
 	}
 
 	cmd := m.hideConsoleCommand(toolPath, "/SetPrimary", monitorId)
-	err = cmd.Run()
+	_, err = m.run(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to set monitor as primary: %w", err)
 	}
@@ -301,7 +355,7 @@ func (m *MonitorTools) ApplyMonitorConfig(configPath string) error {
 
 	// Execute the save config command
 	cmd := m.hideConsoleCommand(toolPath, "/LoadConfig", configPath)
-	err = cmd.Run()
+	_, err = m.run(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to load monitor configuration: %w", err)
 	}
@@ -0,0 +1,53 @@
+package processes
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// Column positions in the tasklist /fo csv /nh output:
+// "Image Name","PID","Session Name","Session#","Mem Usage"
+const (
+	ColImageName = 0
+	TasklistExe  = "tasklist.exe"
+)
+
+// Lister returns the executable names of the running processes
+type Lister interface {
+	RunningProcesses() ([]string, error)
+}
+
+// ProcessTools lists processes with the tasklist command shipped with Windows
+type ProcessTools struct{}
+
+// NewProcessTools creates a new ProcessTools instance
+func NewProcessTools() *ProcessTools {
+	return &ProcessTools{}
+}
+
+// RunningProcesses returns the image names of all running processes using
+// tasklist /fo csv /nh. Names are returned as reported, e.g. "eldenring.exe".
+func (p *ProcessTools) RunningProcesses() ([]string, error) {
+	cmd := p.hideConsoleCommand(TasklistExe, "/fo", "csv", "/nh")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute tasklist: %w", err)
+	}
+
+	reader := csv.NewReader(strings.NewReader(string(output)))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tasklist output: %w", err)
+	}
+
+	names := make([]string, 0, len(records))
+	for _, row := range records {
+		if len(row) > ColImageName {
+			names = append(names, strings.TrimSpace(row[ColImageName]))
+		}
+	}
+
+	return names, nil
+}
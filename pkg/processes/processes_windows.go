@@ -0,0 +1,18 @@
+//go:build windows
+
+package processes
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// hideConsoleCommand creates a command with hidden console window
+func (p *ProcessTools) hideConsoleCommand(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow: true,
+	}
+
+	return cmd
+}
@@ -0,0 +1,111 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParseCron(t *testing.T, expr string) *Cron {
+	t.Helper()
+	cron, err := ParseCron(expr)
+	if err != nil {
+		t.Fatalf("ParseCron(%q) error = %v", expr, err)
+	}
+	return cron
+}
+
+func date(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestCronNext(t *testing.T) {
+	// 2025-01-01 is a Wednesday
+	from := date(2025, time.January, 1, 10, 30)
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"*/15 * * * *", from, date(2025, time.January, 1, 10, 45)},
+		{"30 10 * * *", from, date(2025, time.January, 2, 10, 30)},
+		{"0 8-18/2 * * *", from, date(2025, time.January, 1, 12, 0)},
+		{"@hourly", from, date(2025, time.January, 1, 11, 0)},
+		{"@monthly", from, date(2025, time.February, 1, 0, 0)},
+		{"0 9 * * MON-FRI", date(2025, time.January, 3, 9, 0), date(2025, time.January, 6, 9, 0)},
+		{"0 0 1 JAN,jul *", from, date(2025, time.July, 1, 0, 0)},
+		// Seconds are dropped, the next run is strictly after the given time
+		{"31 10 * * *", from.Add(59 * time.Second), date(2025, time.January, 1, 10, 31)},
+		// Leap days only match in leap years
+		{"0 0 29 2 *", from, date(2028, time.February, 29, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		if got := mustParseCron(t, tt.expr).Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%q.Next(%s) = %s, want %s", tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestCronNextDayOfMonthOrDayOfWeek(t *testing.T) {
+	// With both day fields restricted a day matches either of them: the 15th
+	// or any Monday
+	cron := mustParseCron(t, "0 12 15 * MON")
+
+	// 2025-01-01 is a Wednesday, Monday the 6th comes before the 15th
+	want := []time.Time{
+		date(2025, time.January, 6, 12, 0),
+		date(2025, time.January, 13, 12, 0),
+		date(2025, time.January, 15, 12, 0),
+		date(2025, time.January, 20, 12, 0),
+	}
+
+	next := date(2025, time.January, 1, 0, 0)
+	for _, w := range want {
+		next = cron.Next(next)
+		if !next.Equal(w) {
+			t.Fatalf("Next() = %s, want %s", next, w)
+		}
+	}
+
+	// A restricted day-of-week with a "*" day-of-month only matches the weekday
+	if got := mustParseCron(t, "0 12 * * MON").Next(date(2025, time.January, 1, 0, 0)); !got.Equal(want[0]) {
+		t.Errorf("Next() = %s, want %s", got, want[0])
+	}
+}
+
+func TestCronSundayAsSeven(t *testing.T) {
+	// 2025-01-05 is a Sunday
+	want := date(2025, time.January, 5, 8, 0)
+	from := date(2025, time.January, 1, 0, 0)
+
+	for _, expr := range []string{"0 8 * * 0", "0 8 * * 7", "0 8 * * SUN", "0 8 * * 6-7"} {
+		got := mustParseCron(t, expr).Next(from)
+		if expr == "0 8 * * 6-7" {
+			// Saturday the 4th comes first
+			if w := date(2025, time.January, 4, 8, 0); !got.Equal(w) {
+				t.Errorf("%q.Next() = %s, want %s", expr, got, w)
+			}
+			got = mustParseCron(t, expr).Next(got)
+		}
+		if !got.Equal(want) {
+			t.Errorf("%q.Next() = %s, want %s", expr, got, want)
+		}
+	}
+}
+
+func TestCronNextImpossible(t *testing.T) {
+	for _, expr := range []string{"0 0 30 2 *", "0 0 31 4 *", "0 0 31 APR,JUN,SEP,NOV *"} {
+		if got := mustParseCron(t, expr).Next(date(2025, time.January, 1, 0, 0)); !got.IsZero() {
+			t.Errorf("%q.Next() = %s, want the zero time", expr, got)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "* * * FOO *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
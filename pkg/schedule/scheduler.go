@@ -0,0 +1,219 @@
+package schedule
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Missed run policies decide what happens to runs that were due while the
+// computer was asleep or the scheduler was blocked
+const (
+	MissedRunSkip   = "skip"       // drop missed runs and wait for the next one
+	MissedRunLatest = "run-latest" // run the most recent missed run once
+)
+
+const (
+	// defaultTolerance is how late a run may fire and still count as on time
+	defaultTolerance = time.Minute
+
+	// maxWait caps every wait so wall clock jumps (sleep, clock changes) are
+	// noticed even when the runtime timer does not advance during sleep
+	maxWait = time.Minute
+)
+
+// Clock abstracts time so the scheduler can be driven by a fake clock in tests
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by the time package
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time                         { return time.Now() }
+func (SystemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Job is a cron schedule with the function to run
+type Job struct {
+	ID              string
+	Cron            *Cron
+	MissedRunPolicy string
+	Run             func(scheduled time.Time, missed bool)
+}
+
+// Run is an upcoming run of a job
+type Run struct {
+	JobID string
+	Time  time.Time
+}
+
+// Scheduler runs jobs at the times given by their cron expressions
+type Scheduler struct {
+	clock     Clock
+	tolerance time.Duration
+
+	mu     sync.Mutex
+	jobs   []Job
+	cursor time.Time
+	reload chan struct{}
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewScheduler creates a scheduler that reads the time from clock
+func NewScheduler(clock Clock) *Scheduler {
+	return &Scheduler{
+		clock:     clock,
+		tolerance: defaultTolerance,
+		reload:    make(chan struct{}, 1),
+	}
+}
+
+// SetJobs replaces the scheduled jobs
+func (s *Scheduler) SetJobs(jobs []Job) {
+	s.mu.Lock()
+	s.jobs = append([]Job(nil), jobs...)
+	s.mu.Unlock()
+
+	// Wake the loop so it recomputes the next due time
+	select {
+	case s.reload <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the scheduler loop in a background goroutine until Stop is called
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopCh != nil {
+		return
+	}
+
+	s.cursor = s.clock.Now()
+	s.stopCh = make(chan struct{})
+	s.doneCh = make(chan struct{})
+
+	go s.loop(s.stopCh, s.doneCh)
+}
+
+// Stop stops the scheduler loop and waits for it to exit
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	stopCh, doneCh := s.stopCh, s.doneCh
+	s.stopCh, s.doneCh = nil, nil
+	s.mu.Unlock()
+
+	if stopCh == nil {
+		return
+	}
+
+	close(stopCh)
+	<-doneCh
+}
+
+func (s *Scheduler) loop(stopCh <-chan struct{}, doneCh chan<- struct{}) {
+	defer close(doneCh)
+
+	for {
+		wait := maxWait
+		if next := s.nextDue(); !next.IsZero() {
+			if until := next.Sub(s.clock.Now()); until < wait {
+				wait = until
+			}
+		}
+		if wait < 0 {
+			wait = 0
+		}
+
+		select {
+		case <-stopCh:
+			return
+		case <-s.reload:
+		case <-s.clock.After(wait):
+		}
+
+		s.Tick()
+	}
+}
+
+// nextDue returns the earliest upcoming run after the cursor
+func (s *Scheduler) nextDue() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var earliest time.Time
+	for _, job := range s.jobs {
+		next := job.Cron.Next(s.cursor)
+		if !next.IsZero() && (earliest.IsZero() || next.Before(earliest)) {
+			earliest = next
+		}
+	}
+	return earliest
+}
+
+// Tick runs every job that became due since the previous tick. A run that is
+// more than the tolerance late counts as missed and follows the job's policy.
+func (s *Scheduler) Tick() {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	cursor := s.cursor
+	if now.After(s.cursor) {
+		s.cursor = now
+	}
+	jobs := append([]Job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		latest := latestRun(job.Cron, cursor, now)
+		if latest.IsZero() {
+			continue
+		}
+
+		missed := now.Sub(latest) > s.tolerance
+		if missed && job.MissedRunPolicy != MissedRunLatest {
+			continue
+		}
+
+		job.Run(latest, missed)
+	}
+}
+
+// latestRun returns the last run in (after, until] or the zero time
+func latestRun(cron *Cron, after, until time.Time) time.Time {
+	var latest time.Time
+	for next := cron.Next(after); !next.IsZero() && !next.After(until); next = cron.Next(next) {
+		latest = next
+	}
+	return latest
+}
+
+// NextRuns returns up to count upcoming runs across all jobs, earliest first
+func (s *Scheduler) NextRuns(count int) []Run {
+	s.mu.Lock()
+	jobs := append([]Job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	now := s.clock.Now()
+	runs := make([]Run, 0, count)
+
+	for _, job := range jobs {
+		next := now
+		for i := 0; i < count; i++ {
+			next = job.Cron.Next(next)
+			if next.IsZero() {
+				break
+			}
+			runs = append(runs, Run{JobID: job.ID, Time: next})
+		}
+	}
+
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Time.Before(runs[j].Time) })
+	if len(runs) > count {
+		runs = runs[:count]
+	}
+	return runs
+}
@@ -0,0 +1,189 @@
+package schedule
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only moves when the test advances it
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After never fires, tests drive the scheduler with Tick
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	return make(chan time.Time)
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// run is a recorded call of a job
+type run struct {
+	scheduled time.Time
+	missed    bool
+}
+
+// recordingJob returns a job that appends its runs to runs
+func recordingJob(t *testing.T, expr string, policy string, runs *[]run) Job {
+	return Job{
+		ID:              expr,
+		Cron:            mustParseCron(t, expr),
+		MissedRunPolicy: policy,
+		Run: func(scheduled time.Time, missed bool) {
+			*runs = append(*runs, run{scheduled, missed})
+		},
+	}
+}
+
+// newTestScheduler returns a scheduler whose cursor starts at the clock's time
+func newTestScheduler(clock *fakeClock, jobs ...Job) *Scheduler {
+	s := NewScheduler(clock)
+	s.SetJobs(jobs)
+	s.cursor = clock.Now()
+	return s
+}
+
+func TestLatestRun(t *testing.T) {
+	cron := mustParseCron(t, "0 * * * *")
+	after := date(2025, time.January, 1, 10, 0)
+
+	tests := []struct {
+		until time.Time
+		want  time.Time
+	}{
+		// The start of the interval is excluded
+		{date(2025, time.January, 1, 10, 0), time.Time{}},
+		{date(2025, time.January, 1, 10, 59), time.Time{}},
+		// The end is included
+		{date(2025, time.January, 1, 11, 0), date(2025, time.January, 1, 11, 0)},
+		{date(2025, time.January, 1, 13, 30), date(2025, time.January, 1, 13, 0)},
+	}
+
+	for _, tt := range tests {
+		if got := latestRun(cron, after, tt.until); !got.Equal(tt.want) {
+			t.Errorf("latestRun(%s, %s) = %s, want %s", after, tt.until, got, tt.want)
+		}
+	}
+
+	if got := latestRun(mustParseCron(t, "0 0 30 2 *"), after, after.AddDate(1, 0, 0)); !got.IsZero() {
+		t.Errorf("latestRun() of an impossible expression = %s, want the zero time", got)
+	}
+}
+
+func TestTickRunsOnTime(t *testing.T) {
+	clock := &fakeClock{now: date(2025, time.January, 1, 8, 59)}
+	var runs []run
+	s := newTestScheduler(clock, recordingJob(t, "0 9 * * *", MissedRunSkip, &runs))
+
+	s.Tick()
+	if len(runs) != 0 {
+		t.Fatalf("runs before 9:00 = %v", runs)
+	}
+
+	// Firing within the tolerance is on time
+	clock.advance(time.Minute + 30*time.Second)
+	s.Tick()
+	if len(runs) != 1 || !runs[0].scheduled.Equal(date(2025, time.January, 1, 9, 0)) || runs[0].missed {
+		t.Fatalf("runs = %v, want the 9:00 run on time", runs)
+	}
+
+	// A run is not repeated on later ticks
+	clock.advance(time.Minute)
+	s.Tick()
+	if len(runs) != 1 {
+		t.Errorf("runs = %v, want a single run", runs)
+	}
+}
+
+func TestTickAfterSleep(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   []run
+	}{
+		// Skipped runs are dropped
+		{MissedRunSkip, nil},
+		// Only the most recent missed run fires, flagged as missed
+		{MissedRunLatest, []run{{date(2025, time.January, 1, 12, 0), true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			clock := &fakeClock{now: date(2025, time.January, 1, 9, 30)}
+			var runs []run
+			s := newTestScheduler(clock, recordingJob(t, "0 * * * *", tt.policy, &runs))
+
+			// The computer sleeps through the 10:00, 11:00 and 12:00 runs
+			clock.advance(3*time.Hour + 10*time.Minute)
+			s.Tick()
+
+			if len(runs) != len(tt.want) {
+				t.Fatalf("runs = %v, want %v", runs, tt.want)
+			}
+			for i := range runs {
+				if !runs[i].scheduled.Equal(tt.want[i].scheduled) || runs[i].missed != tt.want[i].missed {
+					t.Errorf("runs = %v, want %v", runs, tt.want)
+				}
+			}
+
+			// The next run after waking fires on time under either policy
+			runs = nil
+			clock.advance(20 * time.Minute)
+			s.Tick()
+			if len(runs) != 1 || !runs[0].scheduled.Equal(date(2025, time.January, 1, 13, 0)) || runs[0].missed {
+				t.Errorf("runs = %v, want the 13:00 run on time", runs)
+			}
+		})
+	}
+}
+
+func TestTickIgnoresClockGoingBack(t *testing.T) {
+	clock := &fakeClock{now: date(2025, time.January, 1, 9, 1)}
+	var runs []run
+	s := newTestScheduler(clock, recordingJob(t, "0 9 * * *", MissedRunLatest, &runs))
+
+	// The clock is set back before the 9:00 run, which already happened
+	clock.advance(-2 * time.Minute)
+	s.Tick()
+	clock.advance(2 * time.Minute)
+	s.Tick()
+
+	if len(runs) != 0 {
+		t.Errorf("runs = %v, want the 9:00 run not to fire again", runs)
+	}
+}
+
+func TestNextRuns(t *testing.T) {
+	clock := &fakeClock{now: date(2025, time.January, 1, 9, 30)}
+	s := newTestScheduler(clock,
+		Job{ID: "hourly", Cron: mustParseCron(t, "0 * * * *")},
+		Job{ID: "quarter", Cron: mustParseCron(t, "15,45 * * * *")},
+	)
+
+	want := []Run{
+		{"quarter", date(2025, time.January, 1, 9, 45)},
+		{"hourly", date(2025, time.January, 1, 10, 0)},
+		{"quarter", date(2025, time.January, 1, 10, 15)},
+		{"quarter", date(2025, time.January, 1, 10, 45)},
+	}
+	got := s.NextRuns(len(want))
+	if len(got) != len(want) {
+		t.Fatalf("NextRuns() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].JobID != want[i].JobID || !got[i].Time.Equal(want[i].Time) {
+			t.Errorf("NextRuns()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Delivery records the outcome of sending one event to one URL
+type Delivery struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	Event      string    `json:"event"`
+	Time       time.Time `json:"time"` // when the first attempt was made
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"statusCode,omitempty"` // status of the last attempt
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Target is a webhook endpoint. Requests are signed with Secret when it is set.
+type Target struct {
+	URL    string
+	Secret string
+}
+
+// Dispatcher posts events to webhook targets in the background, retrying
+// failed deliveries with exponential backoff
+type Dispatcher struct {
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration // wait before the first retry, doubled for every further retry
+	maxLog      int
+
+	mu      sync.Mutex
+	log     []Delivery
+	nextID  int
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running int        // deliveries in progress, counted under mu so Send never races Stop
+	idle    *sync.Cond // broadcast when running drops to zero
+}
+
+// NewDispatcher creates a dispatcher that makes up to maxAttempts attempts per
+// delivery and keeps the last maxLog deliveries
+func NewDispatcher(maxAttempts int, backoff time.Duration, maxLog int) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		maxLog:      maxLog,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	d.idle = sync.NewCond(&d.mu)
+	return d
+}
+
+// Sign returns the signature header value of body, "sha256=" followed by the
+// hex encoded HMAC-SHA256 of the body keyed with the secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send delivers body to the target in the background
+func (d *Dispatcher) Send(target Target, event string, body []byte) {
+	d.mu.Lock()
+	d.nextID++
+	delivery := Delivery{
+		ID:    fmt.Sprintf("%d-%d", time.Now().UnixNano(), d.nextID),
+		URL:   target.URL,
+		Event: event,
+		Time:  time.Now(),
+	}
+	ctx := d.ctx
+	d.running++
+	d.mu.Unlock()
+
+	go func() {
+		d.record(d.deliver(ctx, target, body, delivery))
+	}()
+}
+
+// deliver makes attempts until one succeeds, the attempts run out or the
+// dispatcher is stopped
+func (d *Dispatcher) deliver(ctx context.Context, target Target, body []byte, delivery Delivery) Delivery {
+	wait := d.backoff
+
+	for {
+		delivery.Attempts++
+		status, err := d.post(ctx, target, body, delivery)
+		delivery.StatusCode = status
+
+		if err == nil {
+			delivery.Success = true
+			delivery.Error = ""
+			return delivery
+		}
+		delivery.Error = err.Error()
+
+		if delivery.Attempts >= d.maxAttempts {
+			return delivery
+		}
+
+		select {
+		case <-ctx.Done():
+			delivery.Error += " (cancelled)"
+			return delivery
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// post makes a single delivery attempt. Any 2xx status counts as delivered.
+func (d *Dispatcher) post(ctx context.Context, target Target, body []byte, delivery Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	if target.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(target.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// record appends a finished delivery to the log, dropping the oldest entries,
+// and wakes up Stop and Drain once no delivery is left
+func (d *Dispatcher) record(delivery Delivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.log = append(d.log, delivery)
+	if len(d.log) > d.maxLog {
+		d.log = d.log[len(d.log)-d.maxLog:]
+	}
+
+	d.running--
+	if d.running == 0 {
+		d.idle.Broadcast()
+	}
+}
+
+// waitIdle waits until no delivery is in progress
+func (d *Dispatcher) waitIdle() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for d.running > 0 {
+		d.idle.Wait()
+	}
+}
+
+// Deliveries returns the finished deliveries, oldest first
+func (d *Dispatcher) Deliveries() []Delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]Delivery{}, d.log...)
+}
+
+// Drain waits up to timeout for running deliveries, retries included, and
+// then stops the dispatcher. It is used before exiting so short-lived runs do
+// not drop their events.
+func (d *Dispatcher) Drain(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		d.waitIdle()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+	d.Stop()
+}
+
+// Stop cancels pending retries and waits for running deliveries to finish.
+// Events sent afterwards are still delivered.
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	d.cancel()
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	d.mu.Unlock()
+
+	d.waitIdle()
+}
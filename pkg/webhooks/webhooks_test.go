@@ -0,0 +1,40 @@
+package webhooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSendWhileStopping sends events while the dispatcher is being stopped,
+// run with -race to detect unsynchronized access
+func TestSendWhileStopping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	d := NewDispatcher(1, time.Millisecond, 1000)
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 25 {
+				d.Send(Target{URL: server.URL}, "test", []byte("{}"))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 5 {
+				d.Stop()
+			}
+		}()
+	}
+	wg.Wait()
+
+	d.Drain(5 * time.Second)
+	if got := len(d.Deliveries()); got != 100 {
+		t.Errorf("deliveries = %d, want 100", got)
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"monitor-profile-manager-wails/pkg/processes"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	PROCESS_WATCH_INTERVAL = 5 * time.Second
+	PROCESS_TRIGGER_STATE  = "process-trigger"
+)
+
+const (
+	TriggerProcessStarted = "process-started"
+	TriggerProcessExited  = "process-exited"
+)
+
+// ProcessRule applies a profile while an executable is running. The state from
+// before the first triggered process started is restored once all have exited.
+type ProcessRule struct {
+	Executable string `json:"executable"` // image name, e.g. "eldenring.exe"
+	Profile    string `json:"profile"`
+}
+
+// normalizeExecutable returns the lower case image name of an executable path or name
+func normalizeExecutable(executable string) string {
+	return strings.ToLower(filepath.Base(strings.TrimSpace(executable)))
+}
+
+// ProcessWatcher polls the running processes and reports watched executables
+// that started or exited since the previous poll
+type ProcessWatcher struct {
+	*poller
+	lister  processes.Lister
+	watched func() []string
+	handle  func(started []string, exited []string)
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+// NewProcessWatcher creates a watcher that checks the executables returned by
+// watched every interval and passes changes to handle
+func NewProcessWatcher(lister processes.Lister, interval time.Duration, watched func() []string, handle func(started []string, exited []string)) *ProcessWatcher {
+	w := &ProcessWatcher{
+		lister:  lister,
+		watched: watched,
+		handle:  handle,
+		running: make(map[string]bool),
+	}
+	w.poller = newPoller("Process watcher", interval, w.Poll)
+	return w
+}
+
+// Poll lists the running processes once and reports watched executables that
+// started or exited. Nothing is listed when no executables are watched.
+func (w *ProcessWatcher) Poll() error {
+	watched := w.watched()
+
+	w.mu.Lock()
+	if len(watched) == 0 && len(w.running) == 0 {
+		w.mu.Unlock()
+		return nil
+	}
+	w.mu.Unlock()
+
+	names, err := w.lister.RunningProcesses()
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]bool, len(names))
+	for _, name := range names {
+		current[normalizeExecutable(name)] = true
+	}
+
+	w.mu.Lock()
+	var started, exited []string
+	stillWatched := make(map[string]bool, len(watched))
+	for _, executable := range watched {
+		executable = normalizeExecutable(executable)
+		stillWatched[executable] = true
+
+		if current[executable] && !w.running[executable] {
+			w.running[executable] = true
+			started = append(started, executable)
+		}
+	}
+	for executable := range w.running {
+		if !current[executable] || !stillWatched[executable] {
+			delete(w.running, executable)
+			exited = append(exited, executable)
+		}
+	}
+	w.mu.Unlock()
+
+	if len(started) > 0 || len(exited) > 0 {
+		w.handle(started, exited)
+	}
+
+	return nil
+}
+
+// activeProcessTrigger is a running process whose rule profile was applied
+type activeProcessTrigger struct {
+	executable string
+	profile    string
+}
+
+// processTriggerState holds the triggers in start order and the state to restore
+type processTriggerState struct {
+	mu     sync.Mutex
+	active []activeProcessTrigger
+	saved  *SavedState
+}
+
+// watchedExecutables returns the executables of all process rules
+func (a *App) watchedExecutables() []string {
+	rules := a.currentSettings().Automation.ProcessRules
+	executables := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		executables = append(executables, rule.Executable)
+	}
+	return executables
+}
+
+// processRuleIndex returns the index of the rule for a normalized executable or -1
+func processRuleIndex(rules []ProcessRule, executable string) int {
+	for i := range rules {
+		if normalizeExecutable(rules[i].Executable) == executable {
+			return i
+		}
+	}
+	return -1
+}
+
+// findProcessRule returns the rule for an executable or nil
+func (a *App) findProcessRule(executable string) *ProcessRule {
+	rules := a.currentSettings().Automation.ProcessRules
+	if i := processRuleIndex(rules, executable); i >= 0 {
+		rule := rules[i]
+		return &rule
+	}
+	return nil
+}
+
+// handleProcessChanges applies rule profiles for started processes and restores
+// the pre-trigger state when the last triggered process exits
+func (a *App) handleProcessChanges(started []string, exited []string) {
+	a.processTriggers.mu.Lock()
+	defer a.processTriggers.mu.Unlock()
+
+	for _, executable := range exited {
+		a.endProcessTrigger(executable)
+	}
+
+	if a.currentSettings().Automation.Paused {
+		return
+	}
+
+	for _, executable := range started {
+		rule := a.findProcessRule(executable)
+		if rule == nil {
+			continue
+		}
+
+		if a.processTriggers.saved == nil {
+			state, err := a.captureState(PROCESS_TRIGGER_STATE)
+			if err != nil {
+				slog.Error("Failed to save state before process started", "executable", executable, "error", err)
+				continue
+			}
+			a.processTriggers.saved = &state
+		}
+
+		a.processTriggers.active = append(a.processTriggers.active, activeProcessTrigger{executable: executable, profile: rule.Profile})
+		a.autoApplyProfile(TriggerProcessStarted, rule.Profile, fmt.Sprintf("process started: %s", executable))
+	}
+}
+
+// endProcessTrigger removes an exited process from the active triggers. The caller
+// must hold processTriggers.mu.
+func (a *App) endProcessTrigger(executable string) {
+	index := -1
+	for i, trigger := range a.processTriggers.active {
+		if trigger.executable == executable {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return
+	}
+
+	wasTop := index == len(a.processTriggers.active)-1
+	a.processTriggers.active = append(a.processTriggers.active[:index], a.processTriggers.active[index+1:]...)
+
+	// Another triggered process is still running, switch to its profile
+	if len(a.processTriggers.active) > 0 {
+		if wasTop {
+			top := a.processTriggers.active[len(a.processTriggers.active)-1]
+			a.autoApplyProfile(TriggerProcessExited, top.profile, fmt.Sprintf("process exited: %s, %s still running", executable, top.executable))
+		}
+		return
+	}
+
+	state := a.processTriggers.saved
+	a.processTriggers.saved = nil
+	if state == nil {
+		return
+	}
+
+	result := AutoApplyResult{
+		Trigger: TriggerProcessExited,
+		Profile: state.Profile,
+		Reason:  fmt.Sprintf("process exited: %s, restored previous state", executable),
+	}
+
+	applyMutex.Lock()
+	err := a.restoreState(*state)
+	applyMutex.Unlock()
+
+	if err != nil {
+		result.Error = err.Error()
+		slog.Error("Failed to restore state after process exited", "executable", executable, "error", err)
+	}
+	if err := a.discardState(*state); err != nil {
+		slog.Warn("Failed to clean up saved state", "error", err)
+	}
+
+	a.emitEvent(EventProfileAutoApplied, result)
+}
+
+// GetProcessRules returns the configured process rules
+func (a *App) GetProcessRules() []ProcessRule {
+	return a.currentSettings().Automation.ProcessRules
+}
+
+// AddProcessRule validates and stores a new process rule
+func (a *App) AddProcessRule(rule ProcessRule) error {
+	rule.Executable = strings.TrimSpace(rule.Executable)
+	if rule.Executable == "" {
+		return fmt.Errorf("process rule needs an executable")
+	}
+
+	if a.findProfile(rule.Profile) == nil {
+		return fmt.Errorf("profile not found: %s", rule.Profile)
+	}
+
+	return a.updateSettings(func(s *Settings) error {
+		if processRuleIndex(s.Automation.ProcessRules, normalizeExecutable(rule.Executable)) >= 0 {
+			return fmt.Errorf("a process rule for %s already exists", rule.Executable)
+		}
+
+		s.Automation.ProcessRules = append(s.Automation.ProcessRules, rule)
+		return nil
+	})
+}
+
+// RemoveProcessRule removes the rule for the given executable
+func (a *App) RemoveProcessRule(executable string) error {
+	executable = normalizeExecutable(executable)
+	return a.updateSettings(func(s *Settings) error {
+		i := processRuleIndex(s.Automation.ProcessRules, executable)
+		if i < 0 {
+			return fmt.Errorf("process rule not found")
+		}
+
+		s.Automation.ProcessRules = append(s.Automation.ProcessRules[:i], s.Automation.ProcessRules[i+1:]...)
+		return nil
+	})
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"monitor-profile-manager-wails/pkg/monitors"
+)
+
+// fakeLister reports the processes set with set
+type fakeLister struct {
+	mu    sync.Mutex
+	names []string
+	calls int
+}
+
+func (l *fakeLister) set(names ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.names = names
+}
+
+func (l *fakeLister) RunningProcesses() ([]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	return append([]string(nil), l.names...), nil
+}
+
+func (l *fakeLister) callCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.calls
+}
+
+func TestProcessWatcherPollsInBackground(t *testing.T) {
+	lister := &fakeLister{}
+	watched := func() []string { return []string{"Game.exe"} }
+
+	var mu sync.Mutex
+	var started []string
+	watcher := NewProcessWatcher(lister, time.Millisecond, watched, func(s []string, _ []string) {
+		mu.Lock()
+		started = append(started, s...)
+		mu.Unlock()
+	})
+
+	lister.set("GAME.EXE")
+	watcher.Start()
+	watcher.Start()
+	waitFor(t, 5*time.Second, "the started process", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(started) > 0
+	})
+	watcher.Stop()
+	watcher.Stop()
+
+	calls := lister.callCount()
+	time.Sleep(20 * time.Millisecond)
+	if lister.callCount() != calls {
+		t.Error("watcher kept polling after Stop")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(started) != 1 || started[0] != "game.exe" {
+		t.Errorf("started = %q, want game.exe once", started)
+	}
+}
+
+func TestProcessWatcherSkipsListingWithoutRules(t *testing.T) {
+	lister := &fakeLister{}
+	watcher := NewProcessWatcher(lister, time.Hour, func() []string { return nil }, func([]string, []string) {
+		t.Error("handle called without watched executables")
+	})
+
+	if err := watcher.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	if lister.callCount() != 0 {
+		t.Errorf("RunningProcesses called %d times, want 0", lister.callCount())
+	}
+}
+
+func TestProcessTriggersStackAndRestore(t *testing.T) {
+	app, tools := newTestApp(t)
+	for _, name := range []string{"Desk", "Game", "Video"} {
+		if err := app.saveCurrentProfile(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, rule := range []ProcessRule{{Executable: "game.exe", Profile: "Game"}, {Executable: "video.exe", Profile: "Video"}} {
+		if err := app.AddProcessRule(rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := app.ApplyProfile("Desk"); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var results []AutoApplyResult
+	app.events.Subscribe(func(event Event) {
+		if event.Type == EventProfileAutoApplied {
+			mu.Lock()
+			results = append(results, event.Data.(AutoApplyResult))
+			mu.Unlock()
+		}
+	})
+
+	lister := &fakeLister{}
+	watcher := NewProcessWatcher(lister, time.Hour, app.watchedExecutables, app.handleProcessChanges)
+	restoreConfig := "/LoadConfig " + filepath.Join(app.getStateDir(), PROCESS_TRIGGER_STATE+"-monitor.cfg")
+
+	steps := []struct {
+		running []string
+		trigger string // trigger of the resulting auto apply, empty for none
+		active  string // last applied profile afterwards
+	}{
+		{[]string{"game.exe"}, TriggerProcessStarted, "Game"},
+		{[]string{"game.exe", "video.exe"}, TriggerProcessStarted, "Video"},
+		// The game was started first, Video stays applied while it exits
+		{[]string{"video.exe", "notepad.exe"}, "", "Video"},
+		{[]string{"video.exe", "game.exe"}, TriggerProcessStarted, "Game"},
+		// The top trigger exits, the one below it is applied again
+		{[]string{"video.exe"}, TriggerProcessExited, "Video"},
+		// The last trigger exits, the state from before the first is restored
+		{nil, TriggerProcessExited, "Desk"},
+	}
+
+	for i, step := range steps {
+		mu.Lock()
+		results = nil
+		mu.Unlock()
+		tools.resetCalls()
+
+		lister.set(step.running...)
+		if err := watcher.Poll(); err != nil {
+			t.Fatal(err)
+		}
+
+		mu.Lock()
+		got := results
+		mu.Unlock()
+		if step.trigger == "" && len(got) != 0 {
+			t.Errorf("step %d: auto applied %+v, want nothing", i, got)
+		}
+		if step.trigger != "" && (len(got) != 1 || got[0].Trigger != step.trigger || got[0].Profile != step.active || got[0].Error != "") {
+			t.Errorf("step %d: auto applied %+v, want %s of %s", i, got, step.trigger, step.active)
+		}
+		if active := app.GetActiveProfile(); active != step.active {
+			t.Errorf("step %d: active profile = %s, want %s", i, active, step.active)
+		}
+	}
+
+	if !tools.called(monitors.MultiMonitorToolExe, restoreConfig) {
+		t.Errorf("MultiMonitorTool calls = %q, want the saved state restored", tools.calls(monitors.MultiMonitorToolExe))
+	}
+
+	app.processTriggers.mu.Lock()
+	defer app.processTriggers.mu.Unlock()
+	if len(app.processTriggers.active) != 0 || app.processTriggers.saved != nil {
+		t.Errorf("process triggers = %+v, %+v, want none left", app.processTriggers.active, app.processTriggers.saved)
+	}
+}
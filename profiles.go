@@ -2,24 +2,93 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 )
 
 const (
 	PROFILE_DIR       = "profiles"
 	PROFILE_FILE_NAME = "profiles.json"
+
+	// Characters Windows does not allow in file names, the profile name is
+	// part of the file name of its monitor configuration
+	PROFILE_NAME_RESERVED_CHARS = `<>:"/\|?*`
 )
 
+// windowsReservedNames are device names Windows does not allow as file
+// names, with or without an extension
+var windowsReservedNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM0", "COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9", "COM¹", "COM²", "COM³",
+	"LPT0", "LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9", "LPT¹", "LPT²", "LPT³",
+}
+
+// errProfileExists is returned when saving a profile under a name in use
+var errProfileExists = errors.New("profile already exists")
+
+// profileNameError reports a profile name that cannot be used
+type profileNameError struct {
+	name   string
+	reason string
+}
+
+func (e *profileNameError) Error() string {
+	if e.name == "" {
+		return e.reason
+	}
+	return fmt.Sprintf("invalid profile name %q: %s", e.name, e.reason)
+}
+
+// validateProfileName rejects names that are empty or could leave the
+// profiles directory or form an invalid file name, including names Windows
+// rewrites or refuses such as "NUL" or "Desk."
+func validateProfileName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return &profileNameError{reason: "profile name cannot be empty"}
+	}
+	if i := strings.IndexAny(name, PROFILE_NAME_RESERVED_CHARS); i >= 0 {
+		return &profileNameError{name: name, reason: fmt.Sprintf("%q is not allowed", string(name[i]))}
+	}
+	if strings.Contains(name, "..") {
+		return &profileNameError{name: name, reason: `".." is not allowed`}
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return &profileNameError{name: name, reason: "control characters are not allowed"}
+		}
+	}
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return &profileNameError{name: name, reason: "names cannot end with a dot or a space"}
+	}
+	base, _, _ := strings.Cut(name, ".")
+	if slices.Contains(windowsReservedNames, strings.ToUpper(strings.TrimRight(base, " "))) {
+		return &profileNameError{name: name, reason: "reserved Windows device names are not allowed"}
+	}
+	return nil
+}
+
 type AudioProfile struct {
-	DefaultOutputDeviceId string `json:"defaultOutputDeviceId"`
+	DefaultOutputDeviceId string          `json:"defaultOutputDeviceId"`
+	DeviceStates          map[string]bool `json:"deviceStates,omitempty"` // deviceID -> enabled
+}
+
+// ApplyFailure is the payload of the apply-failed event
+type ApplyFailure struct {
+	Profile string `json:"profile"`
+	Error   string `json:"error"`
 }
 
 type SaveProfileRequest struct {
-	Name                  string `json:"name"`
-	DefaultOutputDeviceId string `json:"defaultOutputDeviceId"`
+	Name                  string          `json:"name"`
+	DefaultOutputDeviceId string          `json:"defaultOutputDeviceId"`
+	DeviceStates          map[string]bool `json:"deviceStates"`
 }
 
 // MultiMonitorTool has integrated profile management. Therefore we can use the name
@@ -27,20 +96,37 @@ type SaveProfileRequest struct {
 // to save the audio information as part of the profile.
 
 type Profile struct {
-	Name  string       `json:"name"`
-	Audio AudioProfile `json:"audio"`
+	Name      string       `json:"name"`
+	Audio     AudioProfile `json:"audio"`
+	Monitors  []Monitor    `json:"monitors,omitempty"`  // monitor layout at save time, used for drift detection
+	Hotkey    string       `json:"hotkey,omitempty"`    // global key combination that applies the profile, e.g. "Ctrl+Alt+1"
+	PreApply  []ApplyHook  `json:"preApply,omitempty"`  // commands run before the profile is applied
+	PostApply []ApplyHook  `json:"postApply,omitempty"` // commands run after the profile was applied
 }
 
-// SaveProfile saves a monitor profile with the given profile data
+// SaveProfile saves a monitor profile with the given profile data. A profile
+// with the same name is updated, keeping its hotkey and hooks.
 func (a *App) SaveProfile(request SaveProfileRequest) error {
-	if request.Name == "" {
-		return fmt.Errorf("profile name cannot be empty")
+	return a.saveProfile(request, true)
+}
+
+// saveProfile saves a profile, replacing a profile of the same name when
+// replace is set and failing with errProfileExists otherwise
+func (a *App) saveProfile(request SaveProfileRequest, replace bool) error {
+	if err := validateProfileName(request.Name); err != nil {
+		return err
+	}
+
+	// Saving the monitor configuration would overwrite the existing profile's
+	if !replace && a.findProfile(request.Name) != nil {
+		return fmt.Errorf("%w: %s", errProfileExists, request.Name)
 	}
 
 	profile := Profile{
 		Name: request.Name,
 		Audio: AudioProfile{
 			DefaultOutputDeviceId: request.DefaultOutputDeviceId,
+			DeviceStates:          request.DeviceStates,
 		},
 	}
 
@@ -49,22 +135,78 @@ func (a *App) SaveProfile(request SaveProfileRequest) error {
 		return err
 	}
 
-	a.profiles = append(a.profiles, profile)
+	// Keep a snapshot of the saved layout to detect drift later on
+	profile.Monitors, err = a.readMonitors()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	err = a.updateProfiles(func(profiles []Profile) ([]Profile, error) {
+		index := profileIndex(profiles, profile.Name)
+		if index < 0 {
+			return append(profiles, profile), nil
+		}
+		if !replace {
+			return nil, fmt.Errorf("%w: %s", errProfileExists, profile.Name)
+		}
+
+		existing := profiles[index]
+		profile.Hotkey = existing.Hotkey
+		profile.PreApply = existing.PreApply
+		profile.PostApply = existing.PostApply
+		profiles[index] = profile
+		replaced = true
+		return profiles, nil
+	})
 
 	a.sendProfilesUpdatedEvent()
 
-	return a.saveProfilesToDisk()
+	if err != nil {
+		return err
+	}
+
+	if replaced {
+		a.emitEvent(EventProfileUpdated, profile)
+	} else {
+		a.emitEvent(EventProfileCreated, profile)
+	}
+	return nil
 }
 
-func (a *App) DeleteProfile(profileName string) error {
-	newProfiles := make([]Profile, 0)
-	for _, profile := range a.profiles {
-		if profile.Name != profileName {
-			newProfiles = append(newProfiles, profile)
+// saveCurrentProfile saves the current setup as a new profile the same way
+// the UI does, using the current default device and the enabled state of every
+// non-ignored device. A name in use fails with errProfileExists.
+func (a *App) saveCurrentProfile(profileName string) error {
+	devices, err := a.readVisibleAudioDevices()
+	if err != nil {
+		return err
+	}
+
+	request := SaveProfileRequest{
+		Name:         profileName,
+		DeviceStates: make(map[string]bool),
+	}
+	for _, device := range devices {
+		request.DeviceStates[device.ID] = device.IsEnabled
+		if device.IsDefault {
+			request.DefaultOutputDeviceId = device.ID
 		}
 	}
 
-	a.profiles = newProfiles
+	return a.saveProfile(request, false)
+}
+
+func (a *App) DeleteProfile(profileName string) error {
+	if err := validateProfileName(profileName); err != nil {
+		return err
+	}
+
+	err := a.updateProfiles(func(profiles []Profile) ([]Profile, error) {
+		return slices.DeleteFunc(profiles, func(profile Profile) bool {
+			return profile.Name == profileName
+		}), nil
+	})
 
 	// Clean up the monitor .cfg file
 	monitorConfigPath := a.getMonitorConfigPath(profileName)
@@ -72,53 +214,199 @@ func (a *App) DeleteProfile(profileName string) error {
 		return fmt.Errorf("failed to remove monitor config file: %v", err)
 	}
 
-	err := a.saveProfilesToDisk()
 	if err != nil {
 		return err
 	}
 
+	// Release the hotkey of the deleted profile
+	if err := a.registerHotkeys(); err != nil {
+		slog.Error("Failed to register hotkeys", "error", err)
+	}
+
 	a.sendProfilesUpdatedEvent()
 
+	a.emitEvent(EventProfileDeleted, profileName)
 	return nil
 }
 
 // GetProfiles returns the list of saved profiles
 func (a *App) GetProfiles() []Profile {
 	a.loadProfiles()
+	return a.currentProfiles()
+}
+
+// currentProfiles returns the saved profiles. The slice is shared and must not
+// be modified, change profiles with updateProfiles.
+func (a *App) currentProfiles() []Profile {
+	a.stateMu.RLock()
+	defer a.stateMu.RUnlock()
+
 	return a.profiles
 }
 
-// ApplyProfile applies a monitor profile by name
-func (a *App) ApplyProfile(profileName string) error {
-	var profile *Profile
+// updateProfiles replaces the profiles with the result of update, which
+// receives a copy of them, and saves them to disk. The profiles are left
+// unchanged when update fails.
+func (a *App) updateProfiles(update func(profiles []Profile) ([]Profile, error)) error {
+	a.profilesMu.Lock()
+	defer a.profilesMu.Unlock()
+
+	profiles, err := update(slices.Clone(a.currentProfiles()))
+	if err != nil {
+		return err
+	}
+
+	a.stateMu.Lock()
+	a.profiles = profiles
+	a.stateMu.Unlock()
 
-	for i := range a.profiles {
-		p := a.profiles[i]
+	return a.saveProfilesToDisk(profiles)
+}
+
+// profileIndex returns the index of the profile with the given name or -1
+func profileIndex(profiles []Profile, profileName string) int {
+	return slices.IndexFunc(profiles, func(profile Profile) bool {
+		return profile.Name == profileName
+	})
+}
+
+// findProfile returns a copy of the profile with the given name or nil
+func (a *App) findProfile(profileName string) *Profile {
+	for _, p := range a.currentProfiles() {
 		if p.Name == profileName {
-			profile = &p
-			break
+			return &p
 		}
 	}
+	return nil
+}
+
+// ApplyProfile applies a monitor profile by name
+func (a *App) ApplyProfile(profileName string) error {
+	return a.applyProfileFrom(TriggerUI, profileName)
+}
+
+// applyProfileFrom applies a profile, records the application in the apply
+// history with the trigger that started it and emits the outcome
+func (a *App) applyProfileFrom(trigger string, profileName string) error {
+	entry := ApplyHistoryEntry{Time: time.Now(), Profile: profileName, Trigger: trigger, Outcome: ApplyOutcomeSuccess}
+
+	var steps applySteps
+	err := a.applyProfile(profileName, &steps)
+
+	entry.DurationMs = time.Since(entry.Time).Milliseconds()
+	entry.Steps = steps
+	if err != nil {
+		entry.Outcome = ApplyOutcomeFailure
+		entry.Error = err.Error()
+	}
+	if historyErr := a.appendApplyHistory(entry); historyErr != nil {
+		slog.Error("Failed to record apply history", "error", historyErr)
+	}
+
+	if err != nil {
+		a.emitEvent(EventProfileApplyFailed, ApplyFailure{Profile: profileName, Error: err.Error()})
+		return err
+	}
+
+	a.emitEvent(EventProfileApplied, profileName)
+
+	// The tray order depends on how often profiles were applied
+	if a.currentSettings().TrayOrder == TrayOrderMostUsed {
+		a.sendProfilesUpdatedEvent()
+	}
+	return nil
+}
 
+// applyProfile applies the monitor configuration and audio settings of a
+// profile, timing every step
+func (a *App) applyProfile(profileName string, steps *applySteps) (err error) {
+	applyMutex.Lock()
+	defer applyMutex.Unlock()
+
+	profile := a.findProfile(profileName)
 	if profile == nil {
 		return fmt.Errorf("profile not found: %s", profileName)
 	}
 
+	previousProfile := a.currentSettings().LastAppliedProfile
+	if len(profile.PreApply) > 0 {
+		err := steps.run("pre-apply hooks", func() error {
+			return a.runApplyHooks(HookPhasePre, profile.PreApply, profile.Name, previousProfile)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Remember the current state, it can be undone once the apply succeeded
+	var undoState SavedState
+	captureErr := steps.run("save undo state", func() (err error) {
+		undoState, err = a.captureUndoState()
+		return err
+	})
+	if captureErr != nil {
+		slog.Warn("Failed to save state for undo", "error", captureErr)
+	} else {
+		defer func() {
+			if err != nil {
+				if discardErr := a.discardState(undoState); discardErr != nil {
+					slog.Warn("Failed to clean up undo state", "error", discardErr)
+				}
+				return
+			}
+			if pushErr := a.pushUndoState(undoState); pushErr != nil {
+				slog.Warn("Failed to save state for undo", "error", pushErr)
+			}
+		}()
+	}
+
 	// Apply monitor profile
-	err := a.monitorTools.ApplyMonitorConfig(a.getMonitorConfigPath(profileName))
+	err = steps.run("monitors", func() error {
+		return a.monitorTools.ApplyMonitorConfig(a.getMonitorConfigPath(profileName))
+	})
 	if err != nil {
 		return err
 	}
 
+	// Apply audio device states before the default device, a disabled device
+	// cannot be made the default. A device that cannot be changed does not
+	// stop the apply.
+	if len(profile.Audio.DeviceStates) > 0 {
+		err = steps.run("audio device states", func() error {
+			return a.applyAudioDeviceStates(profile.Audio.DeviceStates)
+		})
+		if err != nil {
+			slog.Warn("Failed to apply audio device states", "profile", profile.Name, "error", err)
+		}
+	}
+
 	// Apply audio profile
 	if profile.Audio.DefaultOutputDeviceId != "" {
-		err = a.audioTools.SetPrimaryDevice(profile.Audio.DefaultOutputDeviceId)
+		err = steps.run("default audio device", func() error {
+			return a.audioTools.SetPrimaryDevice(profile.Audio.DefaultOutputDeviceId)
+		})
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	// Remember the profile so drift enforcement knows what to restore
+	err = steps.run("save settings", func() error {
+		return a.updateSettings(func(s *Settings) error {
+			s.LastAppliedProfile = profile.Name
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(profile.PostApply) == 0 {
+		return nil
+	}
+	return steps.run("post-apply hooks", func() error {
+		return a.runApplyHooks(HookPhasePost, profile.PostApply, profile.Name, previousProfile)
+	})
 }
 
 // getProfilesDir returns the directory where profiles are stored
@@ -132,35 +420,37 @@ func (a *App) getProfilesDir() string {
 
 // loadProfiles loads saved monitor profiles from disk
 func (a *App) loadProfiles() {
-	a.profiles = []Profile{}
-	profilesDir := a.getProfilesDir()
-	if err := os.MkdirAll(profilesDir, 0755); err != nil {
-		return
-	}
-
-	data, err := os.ReadFile(path.Join(profilesDir, PROFILE_FILE_NAME))
-	if err != nil {
-		return
-	}
+	a.readProfiles()
+	a.sendProfilesUpdatedEvent()
+}
 
-	var profiles []Profile
+// readProfiles loads saved monitor profiles from disk without notifying the tray
+func (a *App) readProfiles() {
+	a.profilesMu.Lock()
+	defer a.profilesMu.Unlock()
 
-	if err := json.Unmarshal(data, &profiles); err != nil {
-		return
+	profiles := []Profile{}
+	profilesDir := a.getProfilesDir()
+	if err := os.MkdirAll(profilesDir, 0755); err == nil {
+		if data, err := os.ReadFile(path.Join(profilesDir, PROFILE_FILE_NAME)); err == nil {
+			if err := json.Unmarshal(data, &profiles); err != nil {
+				profiles = []Profile{}
+			}
+		}
 	}
 
+	a.stateMu.Lock()
 	a.profiles = profiles
-
-	a.sendProfilesUpdatedEvent()
+	a.stateMu.Unlock()
 }
 
-func (a *App) saveProfilesToDisk() error {
+func (a *App) saveProfilesToDisk(profiles []Profile) error {
 	profilesDir := a.getProfilesDir()
 	if err := os.MkdirAll(profilesDir, 0755); err != nil {
 		return err
 	}
 
-	data, err := json.Marshal(a.profiles)
+	data, err := json.Marshal(profiles)
 	if err != nil {
 		return err
 	}
@@ -169,8 +459,18 @@ func (a *App) saveProfilesToDisk() error {
 }
 
 func (a *App) sendProfilesUpdatedEvent() {
+	// There is no tray to update when running headless
+	if a.headless {
+		return
+	}
+
+	if a.currentSettings().TrayOrder == TrayOrderMostUsed {
+		profilesUpdatedCh <- a.profileNamesByUse()
+		return
+	}
+
 	profileNames := make([]string, 0)
-	for _, profile := range a.profiles {
+	for _, profile := range a.currentProfiles() {
 		profileNames = append(profileNames, profile.Name)
 	}
 	profilesUpdatedCh <- profileNames
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"Desk", "Gaming (4K)", "TV - Living room", "v1.2", "Büro", "Console", "COM10", "LPT", "Nullable"} {
+		if err := validateProfileName(name); err != nil {
+			t.Errorf("validateProfileName(%q) error = %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"", "  ", "..", "../settings", `..\settings`, "a/b", `a\b`, "C:desk", "desk?", "desk*", "a|b", `a"b`, "<desk>", "a..b", "desk\n", "desk\x00", "Desk.", "Desk ", "CON", "nul", "Com1", "LPT9", "COM²", "aux.cfg", "NUL .desk"} {
+		var nameErr *profileNameError
+		if err := validateProfileName(name); !errors.As(err, &nameErr) {
+			t.Errorf("validateProfileName(%q) error = %v, want a profileNameError", name, err)
+		}
+	}
+}
+
+func TestSaveCurrentProfileRejectsDuplicate(t *testing.T) {
+	app, tools := newTestApp(t)
+	if err := app.saveCurrentProfile("Desk"); err != nil {
+		t.Fatal(err)
+	}
+	tools.resetCalls()
+
+	if err := app.saveCurrentProfile("Desk"); !errors.Is(err, errProfileExists) {
+		t.Fatalf("saveCurrentProfile() error = %v, want errProfileExists", err)
+	}
+	if len(app.currentProfiles()) != 1 {
+		t.Errorf("profiles = %+v, want a single Desk", app.currentProfiles())
+	}
+	// The monitor configuration of the existing profile is left alone
+	if calls := tools.calls("MultiMonitorTool.exe"); len(calls) != 0 {
+		t.Errorf("MultiMonitorTool calls = %v, want none", calls)
+	}
+}
+
+func TestSaveProfileReplacesExisting(t *testing.T) {
+	app, _ := newTestApp(t)
+	if err := app.saveCurrentProfile("Desk"); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SetProfileHotkey("Desk", "Ctrl+Alt+1"); err != nil {
+		t.Fatal(err)
+	}
+
+	request := SaveProfileRequest{Name: "Desk", DefaultOutputDeviceId: "Headphones", DeviceStates: map[string]bool{"Headphones": true}}
+	if err := app.SaveProfile(request); err != nil {
+		t.Fatalf("SaveProfile() error = %v", err)
+	}
+
+	profiles := app.currentProfiles()
+	if len(profiles) != 1 {
+		t.Fatalf("profiles = %+v, want a single Desk", profiles)
+	}
+	if profiles[0].Audio.DefaultOutputDeviceId != "Headphones" {
+		t.Errorf("default output = %q, want the updated Headphones", profiles[0].Audio.DefaultOutputDeviceId)
+	}
+	if profiles[0].Hotkey != "Ctrl+Alt+1" {
+		t.Errorf("hotkey = %q, want the existing Ctrl+Alt+1 kept", profiles[0].Hotkey)
+	}
+}
+
+func TestProfileNamesStayInProfilesDirectory(t *testing.T) {
+	app, tools := newTestApp(t)
+
+	// A file next to the profiles directory that a traversing name would reach
+	outside := filepath.Join(filepath.Dir(app.getProfilesDir()), "victim-monitor.cfg")
+	if err := os.WriteFile(outside, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var nameErr *profileNameError
+	if err := app.saveCurrentProfile("../victim"); !errors.As(err, &nameErr) {
+		t.Errorf("saveCurrentProfile() error = %v, want a profileNameError", err)
+	}
+	if err := app.DeleteProfile("../victim"); !errors.As(err, &nameErr) {
+		t.Errorf("DeleteProfile() error = %v, want a profileNameError", err)
+	}
+
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside the profiles directory was touched: %v", err)
+	}
+	if calls := tools.calls("MultiMonitorTool.exe"); len(calls) != 0 {
+		t.Errorf("MultiMonitorTool calls = %v, want none", calls)
+	}
+}
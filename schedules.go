@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"monitor-profile-manager-wails/pkg/schedule"
+	"time"
+)
+
+const (
+	TriggerSchedule = "schedule"
+)
+
+// ScheduleRule applies a profile at the times matching a cron expression
+type ScheduleRule struct {
+	Cron            string `json:"cron"` // five field cron expression, e.g. "0 9 * * MON-FRI"
+	Profile         string `json:"profile"`
+	MissedRunPolicy string `json:"missedRunPolicy"` // "skip" or "run-latest", applied to runs missed during sleep
+}
+
+// ScheduledRun is an upcoming scheduled profile application
+type ScheduledRun struct {
+	Cron    string    `json:"cron"`
+	Profile string    `json:"profile"`
+	Time    time.Time `json:"time"`
+}
+
+// scheduleJobID identifies the scheduler job of a rule
+func scheduleJobID(rule ScheduleRule) string {
+	return rule.Profile + "|" + rule.Cron
+}
+
+// reloadSchedules hands the configured schedule rules to the scheduler
+func (a *App) reloadSchedules() {
+	rules := a.currentSettings().Automation.Schedules
+	jobs := make([]schedule.Job, 0, len(rules))
+
+	for _, rule := range rules {
+		cron, err := schedule.ParseCron(rule.Cron)
+		if err != nil {
+			slog.Warn("Skipping schedule", "profile", rule.Profile, "error", err)
+			continue
+		}
+
+		r := rule
+		jobs = append(jobs, schedule.Job{
+			ID:              scheduleJobID(r),
+			Cron:            cron,
+			MissedRunPolicy: r.MissedRunPolicy,
+			Run: func(scheduled time.Time, missed bool) {
+				a.runSchedule(r, scheduled, missed)
+			},
+		})
+	}
+
+	a.scheduler.SetJobs(jobs)
+}
+
+// runSchedule applies the profile of a due schedule rule
+func (a *App) runSchedule(rule ScheduleRule, scheduled time.Time, missed bool) {
+	if a.currentSettings().Automation.Paused {
+		return
+	}
+
+	reason := fmt.Sprintf("scheduled at %s (%s)", scheduled.Format("2006-01-02 15:04"), rule.Cron)
+	if missed {
+		reason += ", missed run"
+	}
+
+	a.autoApplyProfile(TriggerSchedule, rule.Profile, reason)
+}
+
+// GetSchedules returns the configured schedule rules
+func (a *App) GetSchedules() []ScheduleRule {
+	return a.currentSettings().Automation.Schedules
+}
+
+// AddSchedule validates and stores a new schedule rule
+func (a *App) AddSchedule(rule ScheduleRule) error {
+	if _, err := schedule.ParseCron(rule.Cron); err != nil {
+		return err
+	}
+
+	if a.findProfile(rule.Profile) == nil {
+		return fmt.Errorf("profile not found: %s", rule.Profile)
+	}
+
+	switch rule.MissedRunPolicy {
+	case "":
+		rule.MissedRunPolicy = schedule.MissedRunSkip
+	case schedule.MissedRunSkip, schedule.MissedRunLatest:
+	default:
+		return fmt.Errorf("invalid missed run policy: %s", rule.MissedRunPolicy)
+	}
+
+	err := a.updateSettings(func(s *Settings) error {
+		for _, existing := range s.Automation.Schedules {
+			if scheduleJobID(existing) == scheduleJobID(rule) {
+				return fmt.Errorf("schedule already exists")
+			}
+		}
+
+		s.Automation.Schedules = append(s.Automation.Schedules, rule)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	a.reloadSchedules()
+	return nil
+}
+
+// RemoveSchedule removes the schedule rule with the given cron expression and profile
+func (a *App) RemoveSchedule(cron string, profileName string) error {
+	id := scheduleJobID(ScheduleRule{Cron: cron, Profile: profileName})
+	err := a.updateSettings(func(s *Settings) error {
+		for i, existing := range s.Automation.Schedules {
+			if scheduleJobID(existing) == id {
+				s.Automation.Schedules = append(s.Automation.Schedules[:i], s.Automation.Schedules[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("schedule not found")
+	})
+	if err != nil {
+		return err
+	}
+
+	a.reloadSchedules()
+	return nil
+}
+
+// GetNextScheduledRuns returns up to count upcoming scheduled profile applications
+func (a *App) GetNextScheduledRuns(count int) []ScheduledRun {
+	schedules := a.currentSettings().Automation.Schedules
+	rules := make(map[string]ScheduleRule, len(schedules))
+	for _, rule := range schedules {
+		rules[scheduleJobID(rule)] = rule
+	}
+
+	runs := make([]ScheduledRun, 0, count)
+	for _, run := range a.scheduler.NextRuns(count) {
+		rule := rules[run.JobID]
+		runs = append(runs, ScheduledRun{Cron: rule.Cron, Profile: rule.Profile, Time: run.Time})
+	}
+	return runs
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const (
+	SETTINGS_FILE_NAME = "settings.json"
+)
+
+// Settings holds application wide settings persisted next to the profiles
+type Settings struct {
+	LastAppliedProfile string             `json:"lastAppliedProfile"`
+	LoginProfile       string             `json:"loginProfile"` // applied when started with --login
+	TrayOrder          string             `json:"trayOrder"`    // "" for the saved order or "most-used"
+	LogLevel           string             `json:"logLevel"`     // debug, info, warn or error
+	Enforce            EnforceSettings    `json:"enforce"`
+	Automation         AutomationSettings `json:"automation"`
+	API                APISettings        `json:"api"`
+	MQTT               MQTTSettings       `json:"mqtt"`
+	Webhooks           []Webhook          `json:"webhooks"`
+	Tools              ToolSettings       `json:"tools"`
+}
+
+// defaultSettings returns the settings used when no settings file exists
+func defaultSettings() Settings {
+	return Settings{
+		LogLevel: DEFAULT_LOG_LEVEL,
+		Enforce: EnforceSettings{
+			Enabled:            false,
+			GracePeriodSeconds: 10,
+			MinIntervalSeconds: 60,
+		},
+		Automation: AutomationSettings{
+			Paused:          false,
+			DebounceSeconds: 3,
+			TopologyRules:   []TopologyRule{},
+			AudioRules:      []AudioRule{},
+			Schedules:       []ScheduleRule{},
+			ProcessRules:    []ProcessRule{},
+		},
+		API: APISettings{
+			Enabled: false,
+			Port:    API_DEFAULT_PORT,
+		},
+		MQTT: MQTTSettings{
+			Enabled:         false,
+			TopicPrefix:     MQTT_DEFAULT_TOPIC_PREFIX,
+			Discovery:       true,
+			DiscoveryPrefix: MQTT_DEFAULT_DISCOVERY_PREFIX,
+		},
+		Webhooks: []Webhook{},
+	}
+}
+
+// getSettingsPath returns the path where the settings are stored
+func (a *App) getSettingsPath() string {
+	return filepath.Join(a.getProfilesDir(), SETTINGS_FILE_NAME)
+}
+
+// clone returns a copy that shares no slices with s
+func (s Settings) clone() Settings {
+	s.Automation.TopologyRules = slices.Clone(s.Automation.TopologyRules)
+	s.Automation.AudioRules = slices.Clone(s.Automation.AudioRules)
+	s.Automation.Schedules = slices.Clone(s.Automation.Schedules)
+	s.Automation.ProcessRules = slices.Clone(s.Automation.ProcessRules)
+	s.Webhooks = slices.Clone(s.Webhooks)
+	return s
+}
+
+// currentSettings returns the current settings. The slices in the result are
+// shared and must not be modified, change settings with updateSettings.
+func (a *App) currentSettings() Settings {
+	a.stateMu.RLock()
+	defer a.stateMu.RUnlock()
+
+	return a.settings
+}
+
+// updateSettings applies update to a copy of the settings, then stores and
+// saves the copy. The settings are left unchanged when update fails.
+func (a *App) updateSettings(update func(settings *Settings) error) error {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+
+	settings := a.currentSettings().clone()
+	if err := update(&settings); err != nil {
+		return err
+	}
+
+	a.stateMu.Lock()
+	a.settings = settings
+	a.stateMu.Unlock()
+
+	return a.saveSettings(settings)
+}
+
+// loadSettings loads the settings from disk, falling back to defaults
+func (a *App) loadSettings() {
+	defer a.applyLogLevel()
+	defer a.applyToolPaths()
+
+	settings := defaultSettings()
+	if data, err := os.ReadFile(a.getSettingsPath()); err == nil {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			settings = defaultSettings()
+		}
+	}
+
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+
+	a.stateMu.Lock()
+	a.settings = settings
+	a.stateMu.Unlock()
+}
+
+// saveSettings saves the settings to disk
+func (a *App) saveSettings(settings Settings) error {
+	if err := os.MkdirAll(a.getProfilesDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create settings directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %v", err)
+	}
+
+	if err := os.WriteFile(a.getSettingsPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to save settings: %v", err)
+	}
+
+	return nil
+}
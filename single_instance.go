@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"monitor-profile-manager-wails/pkg/instance"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	INSTANCE_NAME    = "windows-profile-manager"
+	TriggerForwarded = "forwarded"
+)
+
+// claimInstance makes this process the single running instance. When another
+// instance is already running the arguments are forwarded to it and forwarded
+// is true, err then holds the error reported by the running instance.
+func (a *App) claimInstance(args []string) (forwarded bool, err error) {
+	address := instance.Address(INSTANCE_NAME, a.getProfilesDir())
+
+	for attempt := 0; attempt < 2; attempt++ {
+		err := instance.Forward(address, args)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, instance.ErrNotRunning) {
+			return true, err
+		}
+
+		server, err := instance.Listen(address, a.handleForwardedArgs)
+		if err == nil {
+			a.instance = server
+			return false, nil
+		}
+		// Another instance started in between, forward to it instead
+		if !errors.Is(err, instance.ErrAlreadyRunning) {
+			return false, err
+		}
+	}
+
+	return false, fmt.Errorf("could not reach the running instance")
+}
+
+// handleForwardedArgs handles the command line of a second launch. A profile
+// given with --apply or --login is applied, otherwise the window is shown
+// unless --minimized was passed.
+func (a *App) handleForwardedArgs(args []string) error {
+	if a.ctx == nil {
+		return fmt.Errorf("the running instance is still starting")
+	}
+
+	flags, options := newLaunchFlagSet("forwarded", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if profileName, trigger := a.launchProfile(*options); profileName != "" {
+		return a.autoApplyProfile(TriggerForwarded, profileName, fmt.Sprintf("forwarded from a second launch (%s)", trigger))
+	}
+
+	if !options.Minimized {
+		runtime.WindowShow(a.ctx)
+		runtime.WindowUnminimise(a.ctx)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	STATE_DIR = "state"
+)
+
+// SavedState is a snapshot of the monitor configuration and default audio device
+// that can be restored later, e.g. after a process trigger ends
+type SavedState struct {
+	ID                    string    `json:"id"`
+	Time                  time.Time `json:"time"`
+	Profile               string    `json:"profile"`       // last applied profile when the state was captured
+	MonitorConfig         string    `json:"monitorConfig"` // MultiMonitorTool configuration file
+	DefaultOutputDeviceId string    `json:"defaultOutputDeviceId"`
+}
+
+// getStateDir returns the directory where captured monitor configurations are stored
+func (a *App) getStateDir() string {
+	return filepath.Join(a.getProfilesDir(), STATE_DIR)
+}
+
+// captureState saves the current monitor configuration and default audio device
+func (a *App) captureState(id string) (SavedState, error) {
+	stateDir := a.getStateDir()
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return SavedState{}, fmt.Errorf("failed to create state directory: %v", err)
+	}
+
+	state := SavedState{
+		ID:            id,
+		Time:          time.Now(),
+		Profile:       a.currentSettings().LastAppliedProfile,
+		MonitorConfig: filepath.Join(stateDir, id+"-monitor.cfg"),
+	}
+
+	if err := a.monitorTools.SaveMonitorConfig(state.MonitorConfig); err != nil {
+		return SavedState{}, err
+	}
+
+	devices, err := a.readAudioDevices()
+	if err != nil {
+		return SavedState{}, err
+	}
+	for _, device := range devices {
+		if device.IsDefault {
+			state.DefaultOutputDeviceId = device.ID
+			break
+		}
+	}
+
+	return state, nil
+}
+
+// restoreState re-applies a captured state. The caller must hold applyMutex.
+func (a *App) restoreState(state SavedState) error {
+	if err := a.monitorTools.ApplyMonitorConfig(state.MonitorConfig); err != nil {
+		return err
+	}
+
+	if state.DefaultOutputDeviceId != "" {
+		if err := a.audioTools.SetPrimaryDevice(state.DefaultOutputDeviceId); err != nil {
+			return err
+		}
+	}
+
+	return a.updateSettings(func(s *Settings) error {
+		s.LastAppliedProfile = state.Profile
+		return nil
+	})
+}
+
+// discardState removes the files belonging to a captured state
+func (a *App) discardState(state SavedState) error {
+	if err := os.Remove(state.MonitorConfig); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove saved monitor config: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentStateAccess exercises the App state from the goroutines that
+// share it in the running app, run with -race to detect unsynchronized access
+func TestConcurrentStateAccess(t *testing.T) {
+	app, tools := newTestApp(t)
+	tools.setMonitors(fakeMonitor{name: `\\.\DISPLAY1`, id: "DEL4242", displayName: "Dell", active: true, primary: true})
+	tools.setAudio(fakeAudioDevice{id: "Speakers", name: "Speakers", state: "Active", isDefault: true})
+	if err := app.saveCurrentProfile("Desk"); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	run := func(fn func(i int)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				fn(i)
+			}
+		}()
+	}
+
+	// Device watcher
+	run(func(int) { app.snapshotDevices() })
+	// Bound methods
+	run(func(int) {
+		app.GetMonitors()
+		app.GetAudioDevicesWithIgnoreStatus()
+		app.SetMonitorPrimary("DEL4242")
+	})
+	// Settings updates and the event handlers reading them
+	run(func(i int) {
+		url := fmt.Sprintf("http://127.0.0.1:1/hook%d", i)
+		if _, err := app.AddWebhook(Webhook{URL: url, Events: []string{"none"}}); err != nil {
+			t.Error(err)
+		}
+		if err := app.RemoveWebhook(url); err != nil {
+			t.Error(err)
+		}
+	})
+	run(func(int) {
+		app.handleWebhookEvent(Event{Type: EventProfileApplied})
+		app.handleTopologyEvent(Event{Type: EventMonitorAdded})
+		app.GetWebhooks()
+	})
+	// Profile updates and readers from the API, MQTT and hotkeys
+	run(func(i int) {
+		if err := app.SetProfileHotkey("Desk", fmt.Sprintf("Ctrl+Alt+%d", i%10)); err != nil {
+			t.Error(err)
+		}
+	})
+	// Nickname changes from the UI while the watcher applies them
+	run(func(i int) {
+		if err := app.SetMonitorNickname(`\\.\DISPLAY1`, fmt.Sprintf("Left %d", i)); err != nil {
+			t.Error(err)
+		}
+		if err := app.SetAudioDeviceNickname("Speakers", fmt.Sprintf("Desk %d", i)); err != nil {
+			t.Error(err)
+		}
+	})
+	// Ignore rule changes while the watcher and API filter devices
+	run(func(i int) {
+		rule := IgnoreRule{Target: IgnoreTargetAudio, Match: IgnoreMatchGlob, Pattern: fmt.Sprintf("Virtual %d*", i)}
+		if err := app.AddIgnoreRule(rule); err != nil {
+			t.Error(err)
+		}
+		if err := app.RemoveIgnoreRule(rule); err != nil {
+			t.Error(err)
+		}
+	})
+	run(func(int) {
+		app.findProfile("Desk")
+		app.profileNamesByUse()
+		app.registerHotkeys()
+	})
+	wg.Wait()
+
+	if got := len(app.GetWebhooks()); got != 0 {
+		t.Errorf("webhooks left = %d, want 0", got)
+	}
+	if profile := app.findProfile("Desk"); profile == nil || profile.Hotkey != "Ctrl+Alt+9" {
+		t.Errorf("profile = %+v, want the last hotkey Ctrl+Alt+9", profile)
+	}
+}
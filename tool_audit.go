@@ -0,0 +1,70 @@
+package main
+
+import (
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"monitor-profile-manager-wails/pkg/audit"
+	"monitor-profile-manager-wails/pkg/logging"
+)
+
+const (
+	TOOL_AUDIT_FILE_NAME       = "tools.log"
+	TOOL_AUDIT_MAX_SIZE        = 4 * 1024 * 1024
+	TOOL_AUDIT_MAX_FILES       = 2
+	MAX_TOOL_INVOCATIONS       = 500 // invocations kept in memory for GetToolInvocations
+	MAX_TOOL_POLLS             = 50  // device watcher polls kept in memory apart from them
+	DEFAULT_TOOL_AUDIT_RESULTS = 100
+)
+
+// ToolInvocationFilter selects tool invocations, empty fields match everything
+type ToolInvocationFilter struct {
+	Tool         string `json:"tool"` // executable name, e.g. "svcl.exe", case insensitive
+	FailedOnly   bool   `json:"failedOnly"`
+	IncludePolls bool   `json:"includePolls"` // include the last device watcher polls
+	Limit        int    `json:"limit"`        // 0 returns the default of 100
+}
+
+// getToolAuditPath returns the path of the current tool audit file
+func (a *App) getToolAuditPath() string {
+	return filepath.Join(a.getProfilesDir(), LOG_DIRECTORY, TOOL_AUDIT_FILE_NAME)
+}
+
+// startToolAudit appends tool invocations to a rotating file next to the
+// logs. Device watcher polls are only appended when they fail.
+func (a *App) startToolAudit() {
+	file, err := logging.OpenRotatingFile(a.getToolAuditPath(), TOOL_AUDIT_MAX_SIZE, TOOL_AUDIT_MAX_FILES)
+	if err != nil {
+		slog.Warn("Keeping tool invocations in memory only", "error", err)
+		return
+	}
+
+	a.toolAudit.SetPersist(file)
+	a.toolAuditFile = file
+}
+
+// GetToolInvocations returns the most recent MultiMonitorTool and svcl
+// invocations matching the filter, newest first. Device watcher polls are
+// left out unless the filter includes them.
+func (a *App) GetToolInvocations(filter ToolInvocationFilter) []audit.Invocation {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DEFAULT_TOOL_AUDIT_RESULTS
+	}
+
+	recent := a.toolAudit.Recent(filter.IncludePolls)
+	invocations := []audit.Invocation{}
+	for i := len(recent) - 1; i >= 0 && len(invocations) < limit; i-- {
+		invocation := recent[i]
+		if filter.Tool != "" && !strings.EqualFold(invocation.Tool, filter.Tool) {
+			continue
+		}
+		if filter.FailedOnly && invocation.Error == "" {
+			continue
+		}
+		invocations = append(invocations, invocation)
+	}
+
+	return invocations
+}
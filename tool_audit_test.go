@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"monitor-profile-manager-wails/pkg/audio"
+	"monitor-profile-manager-wails/pkg/audit"
+	"monitor-profile-manager-wails/pkg/monitors"
+)
+
+func TestToolInvocationsKeepPollsApart(t *testing.T) {
+	app, tools := newTestApp(t)
+	var persisted bytes.Buffer
+	app.toolAudit.SetPersist(&persisted)
+
+	if _, err := app.snapshotDevices(); err != nil {
+		t.Fatal(err)
+	}
+	app.GetMonitors()
+
+	invocations := app.GetToolInvocations(ToolInvocationFilter{})
+	if len(invocations) != 1 || invocations[0].Tool != monitors.MultiMonitorToolExe || invocations[0].Origin != "" {
+		t.Fatalf("GetToolInvocations() = %+v, want only the MultiMonitorTool run of GetMonitors", invocations)
+	}
+
+	withPolls := app.GetToolInvocations(ToolInvocationFilter{IncludePolls: true})
+	if len(withPolls) != 3 {
+		t.Fatalf("GetToolInvocations(includePolls) returned %d invocations, want 3", len(withPolls))
+	}
+	if withPolls[0].Origin != "" || withPolls[1].Origin != audit.OriginPoll || withPolls[2].Origin != audit.OriginPoll {
+		t.Errorf("origins newest first = %q, %q, %q, want the GetMonitors run before both polls", withPolls[0].Origin, withPolls[1].Origin, withPolls[2].Origin)
+	}
+
+	// Only failed polls are written to the audit file
+	svclCalls := tools.calls(audio.SvclExe)
+	tools.failOn(audio.SvclExe, svclCalls[len(svclCalls)-1])
+	if _, err := app.snapshotDevices(); err == nil {
+		t.Fatal("snapshotDevices() succeeded with a failing svcl")
+	}
+
+	lines := strings.Split(strings.TrimSpace(persisted.String()), "\n")
+	if len(lines) != 2 || strings.Contains(lines[0], `"origin"`) || !strings.Contains(lines[1], `"origin":"poll"`) {
+		t.Errorf("persisted invocations = %q, want the GetMonitors run and the failed poll", lines)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"monitor-profile-manager-wails/pkg/integrity"
+)
+
+const (
+	TOOL_CACHE_DIRECTORY = "tools"
+	TOOL_CACHE_KEY_SIZE  = 16                      // hex characters of the cache key
+	TOOLS_TEMP_PATTERN   = "monitor-profile-tools" // temporary directories used before the cache and as its fallback
+	TOOLS_TEMP_OWNER     = "owner.pid"             // ID of the process using a temporary tool directory
+
+	// TOOLS_TEMP_MIN_AGE protects temporary directories whose owner has not
+	// been written yet, or that were created by builds without owner files
+	TOOLS_TEMP_MIN_AGE = time.Hour
+)
+
+// toolCacheKey returns a key for the recorded hashes of every embedded tool,
+// so a build with different tools extracts to a different directory
+func toolCacheKey(checksums map[string]string) string {
+	var lines strings.Builder
+	for _, tool := range embeddedTools {
+		fmt.Fprintf(&lines, "%s  %s\n", checksums[tool.destPath], tool.destPath)
+	}
+	return integrity.HashBytes([]byte(lines.String()))[:TOOL_CACHE_KEY_SIZE]
+}
+
+// prepareToolCache creates the cache directory for the given checksums under
+// the data directory and removes the directories of other builds
+func (a *App) prepareToolCache(checksums map[string]string) (string, error) {
+	cacheRoot := filepath.Join(a.getProfilesDir(), TOOL_CACHE_DIRECTORY)
+	key := toolCacheKey(checksums)
+
+	cacheDir := filepath.Join(cacheRoot, key)
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create tool cache %s: %v", cacheDir, err)
+	}
+
+	entries, err := os.ReadDir(cacheRoot)
+	if err != nil {
+		return cacheDir, nil
+	}
+	for _, entry := range entries {
+		if entry.Name() == key {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(cacheRoot, entry.Name())); err != nil {
+			slog.Warn("Failed to remove stale tool cache", "dir", entry.Name(), "error", err)
+		}
+	}
+
+	return cacheDir, nil
+}
+
+// createToolsTempDir creates a temporary tool directory owned by this process
+func createToolsTempDir() (string, error) {
+	dir, err := os.MkdirTemp("", TOOLS_TEMP_PATTERN)
+	if err != nil {
+		return "", err
+	}
+
+	owner := []byte(strconv.Itoa(os.Getpid()))
+	if err := os.WriteFile(filepath.Join(dir, TOOLS_TEMP_OWNER), owner, 0644); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to write %s: %v", TOOLS_TEMP_OWNER, err)
+	}
+	return dir, nil
+}
+
+// toolsTempDirInUse reports whether a temporary tool directory may still be
+// used, either by a running owner or because it is too new to have an owner
+func toolsTempDirInUse(dir string, now time.Time) bool {
+	if data, err := os.ReadFile(filepath.Join(dir, TOOLS_TEMP_OWNER)); err == nil {
+		if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil {
+			return pid == os.Getpid() || processRunning(pid)
+		}
+	}
+
+	info, err := os.Stat(dir)
+	return err == nil && now.Sub(info.ModTime()) < TOOLS_TEMP_MIN_AGE
+}
+
+// removeStaleToolsTempDirs removes the temporary tool directories left by
+// earlier runs. Directories of running processes are kept.
+func removeStaleToolsTempDirs() {
+	dirs, _ := filepath.Glob(filepath.Join(os.TempDir(), TOOLS_TEMP_PATTERN+"*"))
+	now := time.Now()
+	for _, dir := range dirs {
+		if toolsTempDirInUse(dir, now) {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			slog.Debug("Failed to remove stale tools directory", "dir", dir, "error", err)
+		}
+	}
+}
+
+// removeToolsTempDir removes the temporary tool directory created by this run
+func (a *App) removeToolsTempDir() {
+	if a.toolsTempDir == "" {
+		return
+	}
+
+	if err := os.RemoveAll(a.toolsTempDir); err != nil {
+		slog.Warn("Failed to remove tools directory", "dir", a.toolsTempDir, "error", err)
+	}
+	a.toolsTempDir = ""
+}
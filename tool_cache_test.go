@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRemoveStaleToolsTempDirsKeepsDirsInUse(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a Unix command for an exited process")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	newDir := func(name string, owner int, age time.Duration) string {
+		dir := filepath.Join(os.TempDir(), TOOLS_TEMP_PATTERN+name)
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if owner != 0 {
+			if err := os.WriteFile(filepath.Join(dir, TOOLS_TEMP_OWNER), []byte(strconv.Itoa(owner)), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		modified := time.Now().Add(-age)
+		if err := os.Chtimes(dir, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	own, err := createToolsTempDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keep := []string{
+		own,
+		newDir("-running", os.Getppid(), 2*TOOLS_TEMP_MIN_AGE),
+		newDir("-new", 0, 0),
+	}
+	remove := []string{
+		newDir("-exited", exited.Process.Pid, 0),
+		newDir("-old", 0, 2*TOOLS_TEMP_MIN_AGE),
+	}
+
+	removeStaleToolsTempDirs()
+
+	for _, dir := range keep {
+		if _, err := os.Stat(dir); err != nil {
+			t.Errorf("%s was removed: %v", filepath.Base(dir), err)
+		}
+	}
+	for _, dir := range remove {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%s was kept", filepath.Base(dir))
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"monitor-profile-manager-wails/pkg/integrity"
+)
+
+// TOOL_CHECKSUMS_PATH is the embedded file written by go generate
+const TOOL_CHECKSUMS_PATH = "tools/SHA256SUMS"
+
+// toolChecksums returns the SHA-256 of every tool recorded at build time,
+// keyed by the path below tools/
+func toolChecksums() (map[string]string, error) {
+	data, err := assets.ReadFile(TOOL_CHECKSUMS_PATH)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded tool checksums, run go generate before building: %v", err)
+	}
+
+	checksums, err := integrity.ParseChecksums(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse embedded tool checksums: %v", err)
+	}
+	return checksums, nil
+}
+
+// verifyEmbeddedTool checks the embedded copy of a tool before it is extracted
+func verifyEmbeddedTool(tool integrity.Tool, data []byte) error {
+	if tool.Expected == "" {
+		return fmt.Errorf("no SHA-256 was recorded for %s at build time", tool.Name)
+	}
+	if actual := integrity.HashBytes(data); actual != tool.Expected {
+		return fmt.Errorf("the embedded %s does not match the SHA-256 recorded at build time (expected %s, got %s)", tool.Name, tool.Expected, actual)
+	}
+	return nil
+}
+
+// handleToolIntegrity logs and emits every change of a tool's integrity state
+func (a *App) handleToolIntegrity(status integrity.Status) {
+	switch status.State {
+	case integrity.StateVerified:
+		slog.Debug("Tool verified", "tool", status.Tool, "sha256", status.Actual)
+	case integrity.StateRestored:
+		slog.Warn("Tool did not match its SHA-256 and was extracted again", "tool", status.Tool, "path", status.Path)
+	case integrity.StateRefused:
+		slog.Error("Tool refused", "tool", status.Tool, "path", status.Path, "error", status.Error)
+	}
+
+	a.emitEvent(EventToolIntegrity, status)
+}
+
+// GetToolStatus returns the last integrity check of every extracted tool.
+// It is empty in development mode where the tools run from the project.
+func (a *App) GetToolStatus() []integrity.Status {
+	return a.toolVerifier.Statuses()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"monitor-profile-manager-wails/pkg/audio"
+	"monitor-profile-manager-wails/pkg/common"
+	"monitor-profile-manager-wails/pkg/monitors"
+)
+
+// ToolSettings overrides where the external tools are run from. Empty paths
+// use the embedded tools, or PATH when they are not available.
+type ToolSettings struct {
+	SvclPath             string `json:"svclPath"`
+	MultiMonitorToolPath string `json:"multiMonitorToolPath"`
+}
+
+// ToolInfo describes the external tool that is in use
+type ToolInfo struct {
+	Tool    string `json:"tool"` // executable name, e.g. "svcl.exe"
+	Path    string `json:"path"`
+	Source  string `json:"source"`            // override, embedded, development or path
+	Version string `json:"version,omitempty"` // file version of the executable
+	Error   string `json:"error,omitempty"`
+}
+
+// applyToolPaths passes the configured tool paths to the tools
+func (a *App) applyToolPaths() {
+	paths := a.currentSettings().Tools
+	if a.audioTools != nil {
+		a.audioTools.SetSvclPath(paths.SvclPath)
+	}
+	if a.monitorTools != nil {
+		a.monitorTools.SetMultiMonitorToolPath(paths.MultiMonitorToolPath)
+	}
+
+	for _, info := range a.GetToolInfo() {
+		if info.Error != "" {
+			slog.Warn("Tool unavailable", "tool", info.Tool, "source", info.Source, "error", info.Error)
+			continue
+		}
+		slog.Info("Using tool", "tool", info.Tool, "path", info.Path, "source", info.Source, "version", info.Version)
+	}
+}
+
+// toolInfo resolves a tool and reads its version
+func toolInfo(tool string, resolve func() (string, string, error)) ToolInfo {
+	path, source, err := resolve()
+	info := ToolInfo{Tool: tool, Path: path, Source: source}
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+
+	// A missing version does not prevent the tool from running
+	if version, err := common.FileVersion(path); err != nil {
+		slog.Debug("Failed to read tool version", "tool", tool, "error", err)
+	} else {
+		info.Version = version
+	}
+	return info
+}
+
+// GetToolInfo returns the path, source and version of svcl and MultiMonitorTool
+func (a *App) GetToolInfo() []ToolInfo {
+	tools := []ToolInfo{}
+	if a.audioTools != nil {
+		tools = append(tools, toolInfo(audio.SvclExe, a.audioTools.ResolveSvclPath))
+	}
+	if a.monitorTools != nil {
+		tools = append(tools, toolInfo(monitors.MultiMonitorToolExe, a.monitorTools.ResolveMultiMonitorToolPath))
+	}
+	return tools
+}
+
+// GetToolPaths returns the configured tool paths
+func (a *App) GetToolPaths() ToolSettings {
+	return a.currentSettings().Tools
+}
+
+// SetToolPath runs a tool, "svcl.exe" or "MultiMonitorTool.exe", from the
+// given path instead of the embedded copy. An empty path removes the override.
+func (a *App) SetToolPath(tool string, path string) error {
+	path = strings.TrimSpace(path)
+	if path != "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("invalid path %s: %v", path, err)
+		}
+		stat, err := os.Stat(absPath)
+		if err != nil {
+			return fmt.Errorf("%s not found: %v", absPath, err)
+		}
+		if stat.IsDir() {
+			return fmt.Errorf("%s is a directory, expected the path of %s", absPath, tool)
+		}
+		path = absPath
+	}
+
+	err := a.updateSettings(func(s *Settings) error {
+		switch {
+		case strings.EqualFold(tool, audio.SvclExe):
+			s.Tools.SvclPath = path
+		case strings.EqualFold(tool, monitors.MultiMonitorToolExe):
+			s.Tools.MultiMonitorToolPath = path
+		default:
+			return fmt.Errorf("unknown tool: %s", tool)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	a.applyToolPaths()
+	return nil
+}
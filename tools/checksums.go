@@ -0,0 +1,43 @@
+//go:build ignore
+
+// checksums records the SHA-256 of the downloaded tools in tools/SHA256SUMS,
+// which is embedded next to the tools and checked when they are extracted
+// and before every run. It is run by go generate from the project root.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const checksumsFile = "tools/SHA256SUMS"
+
+// tools are the embedded tools, relative to the tools directory
+var tools = []string{
+	"svcl/svcl.exe",
+	"multimonitortool/MultiMonitorTool.exe",
+}
+
+func main() {
+	var lines strings.Builder
+	lines.WriteString("# Generated by go generate, do not edit\n")
+
+	for _, tool := range tools {
+		data, err := os.ReadFile(filepath.Join("tools", filepath.FromSlash(tool)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read %s, download it as described in the README: %v\n", tool, err)
+			os.Exit(1)
+		}
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(&lines, "%s  %s\n", hex.EncodeToString(sum[:]), tool)
+	}
+
+	if err := os.WriteFile(checksumsFile, []byte(lines.String()), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", checksumsFile, err)
+		os.Exit(1)
+	}
+}
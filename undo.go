@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const (
+	UNDO_STACK_FILE_NAME = "undo_stack.json"
+	MAX_UNDO_STACK       = 10
+)
+
+// getUndoStackPath returns the path where the undo stack is stored
+func (a *App) getUndoStackPath() string {
+	return filepath.Join(a.getProfilesDir(), UNDO_STACK_FILE_NAME)
+}
+
+// loadUndoStack loads the undo stack from disk
+func (a *App) loadUndoStack() {
+	a.undoStack = []SavedState{}
+
+	data, err := os.ReadFile(a.getUndoStackPath())
+	if err != nil {
+		return
+	}
+
+	var stack []SavedState
+	if err := json.Unmarshal(data, &stack); err != nil {
+		return
+	}
+
+	a.undoStack = stack
+}
+
+// saveUndoStack saves the undo stack to disk
+func (a *App) saveUndoStack() error {
+	data, err := json.MarshalIndent(a.undoStack, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal undo stack: %v", err)
+	}
+
+	if err := os.WriteFile(a.getUndoStackPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to save undo stack: %v", err)
+	}
+
+	return nil
+}
+
+// captureUndoState captures the current state before a profile is applied
+func (a *App) captureUndoState() (SavedState, error) {
+	return a.captureState(fmt.Sprintf("undo-%d", time.Now().UnixNano()))
+}
+
+// pushUndoState adds a state captured before a successful apply to the undo
+// stack. The oldest entries are dropped once the stack exceeds
+// MAX_UNDO_STACK. The caller must hold applyMutex.
+func (a *App) pushUndoState(state SavedState) error {
+	a.undoStack = append(a.undoStack, state)
+	for len(a.undoStack) > MAX_UNDO_STACK {
+		if err := a.discardState(a.undoStack[0]); err != nil {
+			slog.Warn("Failed to clean up undo state", "error", err)
+		}
+		a.undoStack = a.undoStack[1:]
+	}
+
+	return a.saveUndoStack()
+}
+
+// GetUndoStack returns the states that can be restored, oldest first
+func (a *App) GetUndoStack() []SavedState {
+	applyMutex.Lock()
+	defer applyMutex.Unlock()
+
+	return slices.Clone(a.undoStack)
+}
+
+// UndoLastApply restores the state from before the last applied profile
+func (a *App) UndoLastApply() error {
+	applyMutex.Lock()
+	defer applyMutex.Unlock()
+
+	if len(a.undoStack) == 0 {
+		return fmt.Errorf("nothing to undo")
+	}
+
+	state := a.undoStack[len(a.undoStack)-1]
+	if err := a.restoreState(state); err != nil {
+		return err
+	}
+
+	a.undoStack = a.undoStack[:len(a.undoStack)-1]
+	if err := a.discardState(state); err != nil {
+		slog.Warn("Failed to clean up undo state", "error", err)
+	}
+
+	if err := a.saveUndoStack(); err != nil {
+		return err
+	}
+
+	a.emitEvent(EventProfileReverted, state)
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"monitor-profile-manager-wails/pkg/monitors"
+)
+
+func TestUndoStackOnlyKeepsSuccessfulApplies(t *testing.T) {
+	app, tools := newTestApp(t)
+	tools.setMonitors(fakeMonitor{name: `\\.\DISPLAY1`, id: "DEL4242", displayName: "Dell", active: true, primary: true})
+	for _, name := range []string{"Desk", "Broken"} {
+		if err := app.saveCurrentProfile(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	app.loadUndoStack()
+
+	if err := app.ApplyProfile("Desk"); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(app.GetUndoStack()); got != 1 {
+		t.Fatalf("undo stack after a successful apply has %d entries, want 1", got)
+	}
+
+	// A failed apply changed nothing, so there is nothing to undo
+	tools.failOn(monitors.MultiMonitorToolExe, "/LoadConfig "+app.getMonitorConfigPath("Broken"))
+	if err := app.ApplyProfile("Broken"); err == nil {
+		t.Fatal("ApplyProfile() succeeded with a failing MultiMonitorTool")
+	}
+	stack := app.GetUndoStack()
+	if len(stack) != 1 {
+		t.Fatalf("undo stack after a failed apply has %d entries, want 1", len(stack))
+	}
+	entries, err := os.ReadDir(app.getStateDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != stack[0].ID+"-monitor.cfg" {
+		t.Errorf("state directory = %v, want only the config of the successful apply", entries)
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"monitor-profile-manager-wails/pkg/audit"
+)
+
+const (
+	DEVICE_WATCH_INTERVAL = 5 * time.Second
+)
+
+// DeviceSnapshot holds the result of one monitor and audio device enumeration
+type DeviceSnapshot struct {
+	Monitors     []Monitor
+	AudioDevices []AudioDevice
+}
+
+// poller calls poll every interval in a background goroutine, the first time
+// right after Start
+type poller struct {
+	name     string // logged when a poll fails
+	interval time.Duration
+	poll     func() error
+
+	mu     sync.Mutex
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+func newPoller(name string, interval time.Duration, poll func() error) *poller {
+	return &poller{name: name, interval: interval, poll: poll}
+}
+
+// Start runs the poller in a background goroutine until Stop is called
+func (p *poller) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopCh != nil {
+		return
+	}
+
+	p.stopCh = make(chan struct{})
+	p.doneCh = make(chan struct{})
+
+	go p.run(p.stopCh, p.doneCh)
+}
+
+// Stop stops the background goroutine and waits for it to exit
+func (p *poller) Stop() {
+	p.mu.Lock()
+	stopCh, doneCh := p.stopCh, p.doneCh
+	p.stopCh, p.doneCh = nil, nil
+	p.mu.Unlock()
+
+	if stopCh == nil {
+		return
+	}
+
+	close(stopCh)
+	<-doneCh
+}
+
+func (p *poller) run(stopCh <-chan struct{}, doneCh chan<- struct{}) {
+	defer close(doneCh)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.poll(); err != nil {
+			slog.Error(p.name+" failed", "error", err)
+		}
+
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeviceWatcher periodically enumerates monitors and audio devices and publishes
+// an event for every difference to the previous snapshot
+type DeviceWatcher struct {
+	*poller
+	enumerate func() (DeviceSnapshot, error)
+	publish   func(eventType string, data interface{})
+
+	mu       sync.Mutex
+	previous *DeviceSnapshot
+}
+
+// NewDeviceWatcher creates a watcher that calls enumerate every interval and
+// publishes device events through publish
+func NewDeviceWatcher(interval time.Duration, enumerate func() (DeviceSnapshot, error), publish func(eventType string, data interface{})) *DeviceWatcher {
+	w := &DeviceWatcher{
+		enumerate: enumerate,
+		publish:   publish,
+	}
+	w.poller = newPoller("Device watcher", interval, w.Poll)
+	return w
+}
+
+// Poll enumerates devices once and publishes the differences to the previous
+// snapshot. The first successful poll only records the baseline.
+func (w *DeviceWatcher) Poll() error {
+	current, err := w.enumerate()
+	if err != nil {
+		return fmt.Errorf("failed to enumerate devices: %w", err)
+	}
+
+	w.mu.Lock()
+	previous := w.previous
+	w.previous = &current
+	w.mu.Unlock()
+
+	if previous == nil {
+		return nil
+	}
+
+	diffMonitors(previous.Monitors, current.Monitors, w.publish)
+	diffAudioDevices(previous.AudioDevices, current.AudioDevices, w.publish)
+
+	return nil
+}
+
+// monitorKey identifies a monitor across enumerations. The device name alone is
+// reused by Windows when another monitor takes the slot, and two monitors of the
+// same model share the short monitor ID, so both are combined.
+func monitorKey(m Monitor) string {
+	return m.DeviceName + "|" + m.MonitorId
+}
+
+// diffMonitors publishes added, removed and changed events between two monitor lists
+func diffMonitors(previous, current []Monitor, publish func(string, interface{})) {
+	previousByKey := make(map[string]Monitor, len(previous))
+	for _, m := range previous {
+		previousByKey[monitorKey(m)] = m
+	}
+
+	currentKeys := make(map[string]bool, len(current))
+	for i := range current {
+		m := current[i]
+		key := monitorKey(m)
+		currentKeys[key] = true
+
+		old, existed := previousByKey[key]
+		if !existed {
+			publish(EventMonitorAdded, DeviceChange{Monitor: &m})
+			continue
+		}
+
+		if old.IsActive != m.IsActive || old.IsConnected != m.IsConnected || old.IsPrimary != m.IsPrimary || old.DisplayName != m.DisplayName {
+			publish(EventMonitorChanged, DeviceChange{Monitor: &m, PreviousMonitor: &old})
+		}
+	}
+
+	for i := range previous {
+		m := previous[i]
+		if !currentKeys[monitorKey(m)] {
+			publish(EventMonitorRemoved, DeviceChange{PreviousMonitor: &m})
+		}
+	}
+}
+
+// diffAudioDevices publishes added, removed, changed and default-changed events
+// between two audio device lists
+func diffAudioDevices(previous, current []AudioDevice, publish func(string, interface{})) {
+	previousByID := make(map[string]AudioDevice, len(previous))
+	var previousDefault *AudioDevice
+	for i := range previous {
+		previousByID[previous[i].ID] = previous[i]
+		if previous[i].IsDefault {
+			previousDefault = &previous[i]
+		}
+	}
+
+	currentIDs := make(map[string]bool, len(current))
+	var currentDefault *AudioDevice
+	for i := range current {
+		d := current[i]
+		currentIDs[d.ID] = true
+		if d.IsDefault {
+			currentDefault = &current[i]
+		}
+
+		old, existed := previousByID[d.ID]
+		if !existed {
+			publish(EventAudioAdded, DeviceChange{AudioDevice: &d})
+			continue
+		}
+
+		if old.State != d.State || old.IsEnabled != d.IsEnabled || old.Name != d.Name {
+			publish(EventAudioChanged, DeviceChange{AudioDevice: &d, PreviousAudioDevice: &old})
+		}
+	}
+
+	for i := range previous {
+		d := previous[i]
+		if !currentIDs[d.ID] {
+			publish(EventAudioRemoved, DeviceChange{PreviousAudioDevice: &d})
+		}
+	}
+
+	previousDefaultID, currentDefaultID := "", ""
+	if previousDefault != nil {
+		previousDefaultID = previousDefault.ID
+	}
+	if currentDefault != nil {
+		currentDefaultID = currentDefault.ID
+	}
+
+	if previousDefaultID != currentDefaultID {
+		publish(EventAudioDefaultChange, DeviceChange{AudioDevice: currentDefault, PreviousAudioDevice: previousDefault})
+	}
+}
+
+// snapshotDevices enumerates monitors and audio devices for the device watcher,
+// skipping ignored devices. The cached lists are refreshed as a side effect.
+func (a *App) snapshotDevices() (DeviceSnapshot, error) {
+	// Polls are recorded apart so they do not push other tool invocations out
+	monitors, err := a.readMonitorsWith(a.monitorTools.WithOrigin(audit.OriginPoll))
+	if err != nil {
+		return DeviceSnapshot{}, err
+	}
+
+	audioDevices, err := a.readAudioDevicesWith(a.audioTools.WithOrigin(audit.OriginPoll))
+	if err != nil {
+		return DeviceSnapshot{}, err
+	}
+
+	a.storeMonitors(monitors)
+	a.storeAudioDevices(audioDevices)
+
+	filteredAudioDevices := make([]AudioDevice, 0, len(audioDevices))
+	for _, device := range audioDevices {
+		if !a.isAudioDeviceIgnored(device) {
+			filteredAudioDevices = append(filteredAudioDevices, device)
+		}
+	}
+
+	return DeviceSnapshot{
+		Monitors:     a.filterIgnoredMonitors(monitors),
+		AudioDevices: filteredAudioDevices,
+	}, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"monitor-profile-manager-wails/pkg/webhooks"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	WEBHOOK_MAX_ATTEMPTS   = 5
+	WEBHOOK_RETRY_BACKOFF  = 2 * time.Second
+	MAX_WEBHOOK_DELIVERIES = 100
+	WEBHOOK_DRAIN_TIMEOUT  = 10 * time.Second
+)
+
+// defaultWebhookEvents are sent when a webhook does not list its events
+var defaultWebhookEvents = []string{
+	EventProfileApplied,
+	EventProfileApplyFailed,
+	EventProfileCreated,
+	EventProfileUpdated,
+	EventProfileDeleted,
+	EventMonitorAdded,
+	EventMonitorRemoved,
+	EventAudioAdded,
+	EventAudioRemoved,
+}
+
+// Webhook receives a signed JSON POST for every matching event. The body is
+// the event, signed with Secret in the X-Webhook-Signature header.
+type Webhook struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"` // event types to send, empty sends the default set
+}
+
+// wants reports whether the webhook subscribes to an event type
+func (w Webhook) wants(eventType string) bool {
+	events := w.Events
+	if len(events) == 0 {
+		events = defaultWebhookEvents
+	}
+
+	for _, event := range events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// handleWebhookEvent sends the event to every webhook subscribed to it
+func (a *App) handleWebhookEvent(event Event) {
+	configured := a.currentSettings().Webhooks
+	if len(configured) == 0 {
+		return
+	}
+
+	var body []byte
+	for _, webhook := range configured {
+		if !webhook.wants(event.Type) {
+			continue
+		}
+
+		if body == nil {
+			data, err := json.Marshal(event)
+			if err != nil {
+				slog.Error("Failed to marshal webhook event", "event", event.Type, "error", err)
+				return
+			}
+			body = data
+		}
+
+		a.webhooks.Send(webhooks.Target{URL: webhook.URL, Secret: webhook.Secret}, event.Type, body)
+	}
+}
+
+// GetWebhooks returns the configured webhooks
+func (a *App) GetWebhooks() []Webhook {
+	return a.currentSettings().Webhooks
+}
+
+// AddWebhook validates and stores a new webhook and returns it. A random
+// secret is generated when none is given, so every request is signed.
+func (a *App) AddWebhook(webhook Webhook) (Webhook, error) {
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return Webhook{}, fmt.Errorf("invalid webhook URL: %s", webhook.URL)
+	}
+
+	if webhook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return Webhook{}, err
+		}
+		webhook.Secret = secret
+	}
+
+	err = a.updateSettings(func(s *Settings) error {
+		for _, existing := range s.Webhooks {
+			if existing.URL == webhook.URL {
+				return fmt.Errorf("a webhook for %s already exists", webhook.URL)
+			}
+		}
+
+		s.Webhooks = append(s.Webhooks, webhook)
+		return nil
+	})
+	if err != nil {
+		return Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// generateWebhookSecret returns a random secret for signing webhook requests
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// RemoveWebhook removes the webhook with the given URL
+func (a *App) RemoveWebhook(webhookURL string) error {
+	return a.updateSettings(func(s *Settings) error {
+		for i, existing := range s.Webhooks {
+			if existing.URL == webhookURL {
+				s.Webhooks = append(s.Webhooks[:i], s.Webhooks[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("webhook not found")
+	})
+}
+
+// GetWebhookDeliveries returns the most recent webhook deliveries, oldest first
+func (a *App) GetWebhookDeliveries() []webhooks.Delivery {
+	return a.webhooks.Deliveries()
+}
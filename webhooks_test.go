@@ -0,0 +1,75 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"monitor-profile-manager-wails/pkg/webhooks"
+)
+
+func TestAddWebhookGeneratesSecret(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	added, err := app.AddWebhook(Webhook{URL: " http://localhost:9000/hook "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.URL != "http://localhost:9000/hook" || len(added.Secret) != 64 {
+		t.Errorf("AddWebhook() = %+v, want a trimmed URL and a generated secret", added)
+	}
+
+	kept, err := app.AddWebhook(Webhook{URL: "http://localhost:9001/hook", Secret: "mine"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept.Secret != "mine" {
+		t.Errorf("AddWebhook() secret = %q, want the given secret", kept.Secret)
+	}
+
+	if stored := app.GetWebhooks(); len(stored) != 2 || stored[0].Secret != added.Secret {
+		t.Errorf("GetWebhooks() = %+v, want both webhooks with their secrets", stored)
+	}
+
+	if _, err := app.AddWebhook(Webhook{URL: "ftp://localhost/hook"}); err == nil {
+		t.Error("AddWebhook() accepted a non-HTTP URL")
+	}
+}
+
+func TestRunCLIDeliversWebhooksBeforeReturning(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	var mu sync.Mutex
+	var events, signatures []string
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		events = append(events, r.Header.Get(webhooks.EventHeader))
+		signatures = append(signatures, r.Header.Get(webhooks.SignatureHeader))
+		bodies = append(bodies, body)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	webhook, err := app.AddWebhook(Webhook{URL: server.URL, Events: []string{EventProfileCreated}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code, output := runCLICapture(t, app, "save", "Desk"); code != EXIT_OK {
+		t.Fatalf("save = %d, %s", code, output)
+	}
+
+	// The delivery must have finished before runCLI returned
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 || events[0] != EventProfileCreated {
+		t.Fatalf("received events %v, want a single %s", events, EventProfileCreated)
+	}
+	if signatures[0] != webhooks.Sign(webhook.Secret, bodies[0]) {
+		t.Errorf("signature = %q, want the body signed with the generated secret", signatures[0])
+	}
+}